@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	ErrBatchMsg = "error expanding abbreviation #%d"
+)
+
+// expandBatch reads abbreviations from r, one per line or NUL-separated, and writes their
+// expansions to w separated by delimiter. Empty input items result in empty expansions.
+func expandBatch(r io.Reader, w io.Writer, expandFn expander, nulSeparated bool, delimiter string) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), 1024*1024) // nolint: gomnd
+
+	if nulSeparated {
+		scanner.Split(scanNul)
+	}
+
+	for idx := 0; scanner.Scan(); idx++ {
+		abbr := strings.TrimSpace(scanner.Text())
+
+		got := ""
+
+		if abbr != "" {
+			var err error
+
+			got, err = expandFn(abbr)
+			if err != nil {
+				return errors.Wrapf(err, ErrBatchMsg, idx+1)
+			}
+		}
+
+		if idx > 0 {
+			if _, err := io.WriteString(w, delimiter); err != nil {
+				return errors.Wrap(err, "failed to write delimiter")
+			}
+		}
+
+		if _, err := io.WriteString(w, got); err != nil {
+			return errors.Wrap(err, "failed to write expansion")
+		}
+	}
+
+	return errors.Wrap(scanner.Err(), "failed to read input")
+}
+
+// scanNul is a bufio.SplitFunc splitting the input on NUL characters.
+func scanNul(data []byte, atEOF bool) (int, []byte, error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+
+	if i := bytes.IndexByte(data, 0); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+
+	if atEOF {
+		return len(data), data, nil
+	}
+
+	return 0, nil, nil
+}
+
+// unescapeDelimiter allows using Go escape sequences (\n, \t, \x00, ...) in delimiters.
+func unescapeDelimiter(delimiter string) string {
+	unquoted, err := strconv.Unquote(`"` + delimiter + `"`)
+	if err != nil {
+		return delimiter
+	}
+
+	return unquoted
+}
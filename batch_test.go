@@ -0,0 +1,90 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandBatch(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		input        string
+		nulSeparated bool
+		delimiter    string
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "lines",
+			args: args{
+				input:     "ul>li*2\np.foo{bar}\n",
+				delimiter: "\n",
+			},
+			want: "<ul><li></li><li></li></ul>\n<p class=\"foo\">bar</p>",
+		},
+		{
+			name: "empty lines are kept",
+			args: args{
+				input:     "br\n\nbr",
+				delimiter: ";",
+			},
+			want: "<br>;;<br>",
+		},
+		{
+			name: "nul separated",
+			args: args{
+				input:        "p>br\x00span",
+				nulSeparated: true,
+				delimiter:    "\x00",
+			},
+			want: "<p><br></p>\x00<span></span>",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			builder := &strings.Builder{}
+
+			expandFn, err := newExpander(expanderConfig{format: FormatText, column: -1})
+			require.NoError(t, err)
+
+			err = expandBatch(strings.NewReader(tt.args.input), builder, expandFn, tt.args.nulSeparated, tt.args.delimiter)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, builder.String())
+		})
+	}
+
+	t.Run("error reports item number", func(t *testing.T) {
+		t.Parallel()
+
+		builder := &strings.Builder{}
+
+		expandFn, err := newExpander(expanderConfig{format: FormatText, column: -1})
+		require.NoError(t, err)
+
+		err = expandBatch(strings.NewReader("p\nul>"), builder, expandFn, false, "\n")
+		require.Error(t, err)
+
+		assert.Contains(t, err.Error(), "#2")
+	})
+}
+
+func TestUnescapeDelimiter(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "\n", unescapeDelimiter(`\n`))
+	assert.Equal(t, "\x00", unescapeDelimiter(`\x00`))
+	assert.Equal(t, "---", unescapeDelimiter("---"))
+	assert.Equal(t, `"`, unescapeDelimiter(`"`))
+}
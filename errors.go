@@ -0,0 +1,54 @@
+package main
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/peteraba/xemmet/expand"
+)
+
+// caretError renders syntax errors together with the failing line of the input and a caret pointing at the
+// failing character.
+type caretError struct {
+	err   error
+	caret string
+}
+
+func withCaret(err error, input string) error {
+	var syntaxErr *expand.SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		return err
+	}
+
+	return &caretError{
+		err:   err,
+		caret: syntaxErr.Caret(input),
+	}
+}
+
+func (e *caretError) Error() string {
+	return e.err.Error() + "\n" + e.caret
+}
+
+func (e *caretError) Unwrap() error {
+	return e.err
+}
+
+// shiftSyntaxError makes the position of syntax errors relative to the line the abbreviation was extracted from.
+func shiftSyntaxError(err error, line string, start int) error {
+	var syntaxErr *expand.SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		return err
+	}
+
+	return errors.Wrap(syntaxErr.Shift(line, start), expand.ErrTokenizingMsg)
+}
+
+func shiftWarnings(warnings []*expand.SyntaxError, line string, start int) []*expand.SyntaxError {
+	shifted := make([]*expand.SyntaxError, 0, len(warnings))
+
+	for _, warning := range warnings {
+		shifted = append(shifted, warning.Shift(line, start))
+	}
+
+	return shifted
+}
@@ -0,0 +1,401 @@
+package expand
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/brianvoe/gofakeit/v6"
+)
+
+func Number(idx, repeat, start int, reverse bool, numbering string) string {
+	if idx == 0 {
+		panic("should not happen")
+	}
+
+	if !reverse && start == 1 && numbering == "" {
+		return ""
+	}
+
+	count := idx + start - 1
+	if reverse {
+		count = repeat - idx + start
+	}
+
+	if numbering == "" {
+		numbering = "$"
+	}
+
+	str := fmt.Sprintf("%0*d", len(numbering), count)
+
+	return str
+}
+
+type AttrValue struct {
+	Type      TokenType
+	Value     string
+	Numbering string
+	Start     int
+	Reverse   bool
+	// Level refers to the counter of a repeated ancestor above the nearest one, e.g. item$~1.
+	Level int
+}
+
+func NewClass(value string) *AttrValue {
+	return &AttrValue{
+		Type:  Class,
+		Value: value,
+		Start: 1,
+	}
+}
+
+func NewID(value string) *AttrValue {
+	return &AttrValue{
+		Type:  ID,
+		Value: value,
+		Start: 1,
+	}
+}
+
+// NewPart creates a part of a numbered attribute value or text, value is the literal text before the numbering.
+func NewPart(value string) *AttrValue {
+	return &AttrValue{
+		Value: value,
+		Start: 1,
+	}
+}
+
+// NewContent creates a placeholder of wrapped content ($#), value is the literal text before it.
+func NewContent(value string) *AttrValue {
+	return &AttrValue{
+		Type:  Content,
+		Value: value,
+		Start: 1,
+	}
+}
+
+func (v *AttrValue) SetStart(start int) *AttrValue {
+	v.Start = start
+
+	return v
+}
+
+func (v *AttrValue) SetNumbering(numbering string) *AttrValue {
+	v.Numbering = numbering
+
+	return v
+}
+
+func (v *AttrValue) SetLevel(level int) *AttrValue {
+	v.Level = level
+
+	return v
+}
+
+func (v *AttrValue) SetReverse(values ...bool) *AttrValue {
+	if len(values) > 0 {
+		v.Reverse = values[0]
+	} else {
+		v.Reverse = true
+	}
+
+	return v
+}
+
+func (v *AttrValue) getValue(idx, repeat int) string {
+	if v == nil {
+		return ""
+	}
+
+	return v.Value + Number(idx, repeat, v.Start, v.Reverse, v.Numbering)
+}
+
+func (v *AttrValue) Clone() *AttrValue {
+	if v == nil {
+		return nil
+	}
+
+	return &AttrValue{
+		Type:      v.Type,
+		Value:     v.Value,
+		Numbering: v.Numbering,
+		Start:     v.Start,
+		Reverse:   v.Reverse,
+		Level:     v.Level,
+	}
+}
+
+type AttrValues []*AttrValue
+
+func (av AttrValues) Clone() AttrValues {
+	newAv := make(AttrValues, 0, len(av))
+
+	for _, a := range av {
+		newAv = append(newAv, a.Clone())
+	}
+
+	return newAv
+}
+
+// booleanAttributes lists the attributes which are true by their presence alone.
+//
+// nolint: gochecknoglobals
+var booleanAttributes = map[string]struct{}{
+	"allowfullscreen": {},
+	"async":           {},
+	"autofocus":       {},
+	"autoplay":        {},
+	"checked":         {},
+	"controls":        {},
+	"default":         {},
+	"defer":           {},
+	"disabled":        {},
+	"formnovalidate":  {},
+	"hidden":          {},
+	"inert":           {},
+	"ismap":           {},
+	"itemscope":       {},
+	"loop":            {},
+	"multiple":        {},
+	"muted":           {},
+	"nomodule":        {},
+	"novalidate":      {},
+	"open":            {},
+	"playsinline":     {},
+	"readonly":        {},
+	"required":        {},
+	"reversed":        {},
+	"selected":        {},
+	"hx-boost":        {},
+	"hx-disable":      {},
+	"hx-history-elt":  {},
+	"hx-preserve":     {},
+}
+
+type Attr struct {
+	Type         TokenType
+	Name         string
+	Value        string
+	DefaultValue string
+	// DefaultPrefix and DefaultSuffix surround the tab stop of DefaultValue, e.g. the .css of ${1:style}.css.
+	DefaultPrefix string
+	DefaultSuffix string
+	HasEqualSign  bool
+	// Boolean forces rendering without a value, e.g. [foo.], known boolean attributes need no marker.
+	Boolean bool
+	// Parts is Value split at its numbering markers, nil if Value has none.
+	Parts AttrValues
+	// Expression is a JSX expression rendered in braces instead of quotes, e.g. [onClick={handleClick}].
+	Expression bool
+}
+
+func NewDefaultAttr(name, defaultValue string) *Attr {
+	return &Attr{
+		Name:         name,
+		DefaultValue: defaultValue,
+		HasEqualSign: true,
+	}
+}
+
+// SetDefaultAffixes sets the text written before and after the tab stop of the default value.
+func (a *Attr) SetDefaultAffixes(prefix, suffix string) *Attr {
+	a.DefaultPrefix = prefix
+	a.DefaultSuffix = suffix
+
+	return a
+}
+
+func NewAttr(name, value string) *Attr {
+	return &Attr{
+		Name:         name,
+		Value:        value,
+		HasEqualSign: true,
+	}
+}
+
+func (a *Attr) SetParts(parts AttrValues) *Attr {
+	a.Parts = parts
+
+	return a
+}
+
+func (a *Attr) HasNoEqualSign() *Attr {
+	if a.Value != "" {
+		panic(fmt.Sprintf("attribute '%s' has no equal sign, but has a Value of '%s'", a.Name, a.Value))
+	}
+
+	a.HasEqualSign = false
+
+	return a
+}
+
+func (a *Attr) SetBoolean() *Attr {
+	a.Boolean = true
+
+	return a
+}
+
+func (a *Attr) SetExpression() *Attr {
+	a.Expression = true
+
+	return a
+}
+
+// IsBoolean tells if the attribute is rendered without a value, e.g. input[disabled]. An explicit equal sign, e.g.
+// input[disabled=], keeps the value, and so do unknown attributes to keep their tab stop, unless marked as boolean.
+func (a *Attr) IsBoolean() bool {
+	if a.Boolean {
+		return true
+	}
+
+	if a.HasEqualSign || a.Value != "" {
+		return false
+	}
+
+	_, ok := booleanAttributes[strings.ToLower(a.Name)]
+
+	return ok
+}
+
+func (a *Attr) Clone() *Attr {
+	return &Attr{
+		Name:          a.Name,
+		Value:         a.Value,
+		DefaultValue:  a.DefaultValue,
+		DefaultPrefix: a.DefaultPrefix,
+		DefaultSuffix: a.DefaultSuffix,
+		HasEqualSign:  a.HasEqualSign,
+		Boolean:       a.Boolean,
+		Parts:         a.Parts.Clone(),
+		Expression:    a.Expression,
+	}
+}
+
+func (a *Attr) GetValue(counter *Counter, opts Options) string {
+	if a == nil || a.Value == "" {
+		return opts.escapeAttr(a.DefaultPrefix) +
+			opts.tabStop(counter.Get(), opts.escapeAttr(a.DefaultValue)) +
+			opts.escapeAttr(a.DefaultSuffix)
+	}
+
+	if len(a.Value) < 5 || a.Value[:5] != "lorem" {
+		return opts.escapeAttr(a.Value)
+	}
+
+	return lorem(a.Value, opts.faker)
+}
+
+// GetExpression returns the expression of a JSX attribute as it is, empty expressions get a tab stop.
+func (a *Attr) GetExpression(counter *Counter, opts Options) string {
+	if a.Value == "" {
+		return opts.tabStop(counter.Get(), "")
+	}
+
+	return a.Value
+}
+
+type AttrList []*Attr
+
+func (al AttrList) Clone() AttrList {
+	newAl := make(AttrList, 0, len(al))
+
+	for _, a := range al {
+		newAl = append(newAl, a.Clone())
+	}
+
+	return newAl
+}
+
+func (al AttrList) Has(name string) bool {
+	for _, a := range al {
+		if a.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+type Text struct {
+	value string
+	// parts is value split at its numbering markers, nil if value has none.
+	parts AttrValues
+	// wrapped makes wrapped content follow value, see Wrap.
+	wrapped bool
+}
+
+func (t *Text) IsEmpty() bool {
+	return t == nil || t.value == "" && !t.wrapped
+}
+
+// withContent returns a copy of the text which is followed by wrapped content.
+func (t *Text) withContent() *Text {
+	text := t.Clone()
+	if text == nil {
+		text = NewText("")
+	}
+
+	text.wrapped = true
+
+	return text
+}
+
+func (t *Text) Clone() *Text {
+	if t == nil {
+		return nil
+	}
+
+	return &Text{
+		value:   t.value,
+		parts:   t.parts.Clone(),
+		wrapped: t.wrapped,
+	}
+}
+
+const loremKeyword = "lorem"
+
+func (t *Text) GetValue(opts Options) string {
+	if t == nil {
+		return ""
+	}
+
+	if !strings.HasPrefix(t.value, loremKeyword) {
+		return opts.escapeText(t.value)
+	}
+
+	return lorem(t.value, opts.faker)
+}
+
+func NewText(value string) *Text {
+	return &Text{
+		value: value,
+	}
+}
+
+func (t *Text) SetParts(parts AttrValues) *Text {
+	t.parts = parts
+
+	return t
+}
+
+const defaultWordCount = 5
+
+func lorem(expression string, faker *gofakeit.Faker) string {
+	var (
+		words = defaultWordCount
+		err   error
+	)
+
+	if len(expression) > len(loremKeyword) {
+		words, err = strconv.Atoi(expression[len(loremKeyword):])
+		if err != nil {
+			words = defaultWordCount
+		}
+	}
+
+	if faker == nil {
+		return gofakeit.LoremIpsumSentence(words)
+	}
+
+	return faker.LoremIpsumSentence(words)
+}
@@ -1,4 +1,4 @@
-package main
+package expand
 
 import (
 	"strings"
@@ -107,7 +107,7 @@ func TestAttr_GetValue(t *testing.T) {
 	t.Run("lorem ipsum", func(t *testing.T) {
 		t.Parallel()
 
-		got := NewAttr("foo", "lorem").GetValue(counter, "")
+		got := NewAttr("foo", "lorem").GetValue(counter, Options{})
 
 		assert.NotEmpty(t, got)
 		assert.Equal(t, 4, strings.Count(got, " "))
@@ -116,7 +116,7 @@ func TestAttr_GetValue(t *testing.T) {
 	t.Run("lorem ipsum 25", func(t *testing.T) {
 		t.Parallel()
 
-		got := NewAttr("foo", "lorem25").GetValue(counter, "")
+		got := NewAttr("foo", "lorem25").GetValue(counter, Options{})
 
 		assert.NotEmpty(t, got)
 		assert.Equal(t, 24, strings.Count(got, " "))
@@ -156,20 +156,75 @@ func TestAttr_GetValue(t *testing.T) {
 
 			counter := NewCounter()
 
-			got := tt.sut.GetValue(counter, tt.args.tabStopWrapper)
+			got := tt.sut.GetValue(counter, Options{TabStopWrapper: tt.args.tabStopWrapper})
 
 			assert.Equal(t, tt.want, got)
 		})
 	}
 }
 
+func TestAttr_IsBoolean(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		sut  *Attr
+		want bool
+	}{
+		{
+			name: "boolean attribute without equal sign",
+			sut:  NewAttr("disabled", "").HasNoEqualSign(),
+			want: true,
+		},
+		{
+			name: "case insensitive",
+			sut:  NewAttr("Checked", "").HasNoEqualSign(),
+			want: true,
+		},
+		{
+			name: "htmx attribute",
+			sut:  NewAttr("hx-boost", "").HasNoEqualSign(),
+			want: true,
+		},
+		{
+			name: "explicit equal sign",
+			sut:  NewAttr("disabled", ""),
+			want: false,
+		},
+		{
+			name: "value",
+			sut:  NewAttr("disabled", "disabled"),
+			want: false,
+		},
+		{
+			name: "unknown attribute",
+			sut:  NewAttr("title", "").HasNoEqualSign(),
+			want: false,
+		},
+		{
+			name: "unknown attribute with boolean marker",
+			sut:  NewAttr("foo", "").HasNoEqualSign().SetBoolean(),
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, tt.sut.IsBoolean())
+		})
+	}
+}
+
 func TestText_GetValue(t *testing.T) {
 	t.Parallel()
 
 	t.Run("lorem ipsum", func(t *testing.T) {
 		t.Parallel()
 
-		got := NewText("lorem").GetValue()
+		got := NewText("lorem").GetValue(Options{})
 
 		assert.NotEmpty(t, got)
 		assert.Equal(t, 4, strings.Count(got, " "))
@@ -178,7 +233,7 @@ func TestText_GetValue(t *testing.T) {
 	t.Run("lorem ipsum 25", func(t *testing.T) {
 		t.Parallel()
 
-		got := NewText("lorem25").GetValue()
+		got := NewText("lorem25").GetValue(Options{})
 
 		assert.NotEmpty(t, got)
 		assert.Equal(t, 24, strings.Count(got, " "))
@@ -206,7 +261,7 @@ func TestText_GetValue(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			got := tt.sut.GetValue()
+			got := tt.sut.GetValue(Options{})
 
 			assert.Equal(t, tt.want, got)
 		})
@@ -0,0 +1,128 @@
+package expand
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Separators used in the class names resolved by the BEM filter, e.g. card__body--active.
+const (
+	bemElementSeparator  = "__"
+	bemModifierSeparator = "--"
+)
+
+// bemModifiers splits the modifiers off element and modifier shorthands, e.g. -body_active or --active.
+//
+// nolint: gochecknoglobals
+var bemModifiers = regexp.MustCompile(`--|_`)
+
+// bemClassKind tells the kind of shorthand a class is written in for the BEM filter.
+type bemClassKind int
+
+const (
+	bemPlain bemClassKind = iota
+	bemElement
+	bemModifier
+)
+
+// parseBEMClass splits class into its kind and the name following the prefix: elements start with - or __, modifiers
+// with _ or --.
+func parseBEMClass(class string) (bemClassKind, string) {
+	switch {
+	case strings.HasPrefix(class, bemElementSeparator):
+		return bemElement, class[len(bemElementSeparator):]
+	case strings.HasPrefix(class, bemModifierSeparator):
+		return bemModifier, class[len(bemModifierSeparator):]
+	case strings.HasPrefix(class, "-"):
+		return bemElement, class[1:]
+	case strings.HasPrefix(class, "_"):
+		return bemModifier, class[1:]
+	}
+
+	return bemPlain, class
+}
+
+// bem resolves the element and modifier shorthands in the classes of the elements, block is the one of their parent.
+func (el ElemList) bem(block string) {
+	for _, e := range el {
+		e.bem(block)
+	}
+}
+
+// bem replaces the shorthands of the classes of e with full BEM class names: element shorthands are prefixed with the
+// block of the closest ancestor having one, modifiers with the element or block of e itself. Shorthands which can not
+// be resolved are kept as they are.
+func (e *Elem) bem(parentBlock string) {
+	values := make([]string, 0, len(e.Classes))
+	for _, class := range e.Classes {
+		values = append(values, class.getValue(e.repetition(class.Level)))
+	}
+
+	block := bemBlock(values)
+
+	var (
+		base    = block
+		classes AttrValues
+		seen    = map[string]struct{}{}
+	)
+
+	add := func(value string) {
+		if _, ok := seen[value]; ok {
+			return
+		}
+
+		seen[value] = struct{}{}
+
+		classes = append(classes, NewClass(value))
+	}
+
+	for _, value := range values {
+		kind, name := parseBEMClass(value)
+		parts := bemModifiers.Split(name, -1)
+
+		switch {
+		case kind == bemElement && parentBlock != "" && parts[0] != "":
+			base = parentBlock + bemElementSeparator + parts[0]
+			add(base)
+			parts = parts[1:]
+
+		case kind == bemModifier && base != "":
+			// all parts are modifiers of the element or block itself
+
+		default:
+			add(value)
+
+			continue
+		}
+
+		for _, modifier := range parts {
+			if modifier != "" {
+				add(base + bemModifierSeparator + modifier)
+			}
+		}
+	}
+
+	e.Classes = classes
+
+	if block == "" {
+		block = parentBlock
+	}
+
+	e.Children.bem(block)
+}
+
+// bemBlock returns the first class of values naming a block, without its modifier.
+func bemBlock(values []string) string {
+	for _, value := range values {
+		kind, _ := parseBEMClass(value)
+		if kind != bemPlain || strings.Contains(value, bemElementSeparator) {
+			continue
+		}
+
+		block, _, _ := strings.Cut(value, bemModifierSeparator)
+
+		return block
+	}
+
+	return ""
+}
@@ -0,0 +1,82 @@
+package expand
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpand_BEMFilter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		abbr string
+		want string
+	}{
+		{
+			name: "elements and modifiers",
+			abbr: "div.card>.-title+.-body_active|bem",
+			want: `<div class="card"><div class="card__title"></div><div class="card__body card__body--active"></div></div>`,
+		},
+		{
+			name: "explicit separators",
+			abbr: "div.card>.__title+.-body--active--open|bem",
+			want: `<div class="card"><div class="card__title"></div>` +
+				`<div class="card__body card__body--active card__body--open"></div></div>`,
+		},
+		{
+			name: "block modifier",
+			abbr: "div.card._big|bem",
+			want: `<div class="card card--big"></div>`,
+		},
+		{
+			name: "element modifier",
+			abbr: "div.card>.-title._large|bem",
+			want: `<div class="card"><div class="card__title card__title--large"></div></div>`,
+		},
+		{
+			name: "elements of elements belong to the block",
+			abbr: "div.card>.-title>.-text|bem",
+			want: `<div class="card"><div class="card__title"><div class="card__text"></div></div></div>`,
+		},
+		{
+			name: "closest block",
+			abbr: "div.card>div.menu--open>.-item|bem",
+			want: `<div class="card"><div class="menu--open"><div class="menu__item"></div></div></div>`,
+		},
+		{
+			name: "numbered block",
+			abbr: "ul.list$*2>li.-item|bem",
+			want: `<ul class="list1"><li class="list1__item"></li></ul><ul class="list2"><li class="list2__item"></li></ul>`,
+		},
+		{
+			name: "without a block",
+			abbr: ".-title._x|bem",
+			want: `<div class="-title _x"></div>`,
+		},
+		{
+			name: "with comments",
+			abbr: "div.card>.-title|bem|c",
+			want: `<div class="card"><div class="card__title"></div><!-- /.card__title --></div><!-- /.card -->`,
+		},
+		{
+			name: "no filter",
+			abbr: "div.card>.-title",
+			want: `<div class="card"><div class="-title"></div></div>`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Expand(tt.abbr, Options{Mode: ModeHTML})
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
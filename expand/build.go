@@ -0,0 +1,132 @@
+package expand
+
+// Repetition is the position of an element among the copies made by its nearest repeated ancestor (or itself).
+type Repetition struct {
+	Num   int
+	Count int
+	// Wrapped is set for implicitly repeated tokens, which get one line of wrapped content per copy.
+	Wrapped bool
+}
+
+func Build(tokens []Token, num, siblingCount int) ElemList {
+	return build(tokens, Repetition{Num: num, Count: siblingCount}, nil)
+}
+
+func build(tokens []Token, current Repetition, outer []Repetition) ElemList {
+	if tokens == nil {
+		return nil
+	}
+
+	var (
+		elemList    = ElemList{}
+		newElemList ElemList
+	)
+
+	for _, token := range tokens {
+		switch value := token.(type) {
+		case *GroupToken:
+			newElemList = buildFromGroup(value, current, outer)
+
+			elemList = append(elemList, newElemList...)
+
+		case *TagToken:
+			newElemList = buildFromTag(value, current, outer)
+
+			elemList = append(elemList, newElemList...)
+
+		case *RawToken:
+			elemList = append(elemList, &Elem{
+				Raw:          value.Value,
+				Num:          current.Num,
+				SiblingCount: current.Count,
+				Outer:        outer,
+			})
+		}
+	}
+
+	return elemList
+}
+
+func BuildFromGroup(token *GroupToken, num, siblingCount int) ElemList {
+	return buildFromGroup(token, Repetition{Num: num, Count: siblingCount}, nil)
+}
+
+func buildFromGroup(token *GroupToken, current Repetition, outer []Repetition) ElemList {
+	if token.GetRepeat() == 1 && !token.ImplicitRepeat {
+		return build(token.Children, current, outer)
+	}
+
+	elemList := ElemList{}
+	outer = nest(current, outer)
+
+	for i := 1; i <= token.GetRepeat(); i++ {
+		repetition := Repetition{Num: i, Count: token.GetRepeat(), Wrapped: token.ImplicitRepeat}
+
+		elemList = append(elemList, build(token.Children, repetition, outer)...)
+	}
+
+	return elemList
+}
+
+func BuildFromTag(token *TagToken, num, siblingCount int) ElemList {
+	return buildFromTag(token, Repetition{Num: num, Count: siblingCount}, nil)
+}
+
+func buildFromTag(token *TagToken, current Repetition, outer []Repetition) ElemList {
+	elemList := ElemList{}
+
+	repeated := token.Repeat > 1 || token.ImplicitRepeat
+
+	if repeated {
+		outer = nest(current, outer)
+	}
+
+	for i := 1; i <= token.Repeat; i++ {
+		if repeated {
+			current = Repetition{Num: i, Count: token.Repeat, Wrapped: token.ImplicitRepeat}
+		}
+
+		elem := &Elem{
+			Name:         token.Name,
+			ID:           token.ID,
+			Classes:      token.Classes,
+			Attributes:   token.Attributes,
+			Text:         token.Text,
+			Children:     build(token.Children, current, outer),
+			Num:          current.Num,
+			SiblingCount: current.Count,
+			Outer:        outer,
+			WrapIndex:    wrapIndex(current, outer),
+		}
+
+		elemList = append(elemList, elem)
+	}
+
+	return elemList
+}
+
+// nest adds current to the outer repetitions when entering a repeated token. Only repeated tokens count as levels.
+func nest(current Repetition, outer []Repetition) []Repetition {
+	if current.Count <= 1 && !current.Wrapped {
+		return outer
+	}
+
+	// the full slice expression makes sure siblings never share the backing array
+	return append(outer[:len(outer):len(outer)], current)
+}
+
+// wrapIndex returns the copy number of the innermost implicitly repeated token, which is also the line of wrapped
+// content belonging to it, 0 if there's none.
+func wrapIndex(current Repetition, outer []Repetition) int {
+	if current.Wrapped {
+		return current.Num
+	}
+
+	for i := len(outer) - 1; i >= 0; i-- {
+		if outer[i].Wrapped {
+			return outer[i].Num
+		}
+	}
+
+	return 0
+}
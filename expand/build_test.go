@@ -1,10 +1,11 @@
-package main
+package expand
 
 import (
 	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestBuild(t *testing.T) {
@@ -378,15 +379,9 @@ func TestBuild(t *testing.T) {
 				siblingCount:   1,
 			},
 			wantHTML: `<ul>
-  <li class="item01">
-    <a></a>
-  </li>
-  <li class="item02">
-    <a></a>
-  </li>
-  <li class="item03">
-    <a></a>
-  </li>
+  <li class="item01"><a></a></li>
+  <li class="item02"><a></a></li>
+  <li class="item03"><a></a></li>
 </ul>
 `,
 			wantErr: assert.NoError,
@@ -412,15 +407,9 @@ func TestBuild(t *testing.T) {
 				siblingCount:   1,
 			},
 			wantHTML: `<ul>
-  <li class="item01">
-    <a></a>
-  </li>
-  <li class="item02">
-    <a></a>
-  </li>
-  <li class="item03">
-    <a></a>
-  </li>
+  <li class="item01"><a></a></li>
+  <li class="item02"><a></a></li>
+  <li class="item03"><a></a></li>
 </ul>
 `,
 			wantErr: assert.NoError,
@@ -446,19 +435,13 @@ func TestBuild(t *testing.T) {
 				siblingCount:   1,
 			},
 			wantHTML: `<div class="div03">
-  <p class="item01">
-    <span></span>
-  </p>
+  <p class="item01"><span></span></p>
 </div>
 <div class="div02">
-  <p class="item02">
-    <span></span>
-  </p>
+  <p class="item02"><span></span></p>
 </div>
 <div class="div01">
-  <p class="item03">
-    <span></span>
-  </p>
+  <p class="item03"><span></span></p>
 </div>
 `,
 			wantErr: assert.NoError,
@@ -497,12 +480,8 @@ func TestBuild(t *testing.T) {
 				num:            1,
 				siblingCount:   1,
 			},
-			wantHTML: `  <td>
-    <span class="span09"></span>
-  </td>
-  <td>
-    <span class="span08"></span>
-  </td>
+			wantHTML: `  <td><span class="span09"></span></td>
+  <td><span class="span08"></span></td>
 `,
 		},
 		{
@@ -525,28 +504,16 @@ func TestBuild(t *testing.T) {
 				siblingCount:   1,
 			},
 			wantHTML: `<tr>
-  <td>
-    <span class="span09"></span>
-  </td>
-  <td>
-    <span class="span08"></span>
-  </td>
+  <td><span class="span09"></span></td>
+  <td><span class="span08"></span></td>
 </tr>
 <tr>
-  <td>
-    <span class="span09"></span>
-  </td>
-  <td>
-    <span class="span08"></span>
-  </td>
+  <td><span class="span09"></span></td>
+  <td><span class="span08"></span></td>
 </tr>
 <tr>
-  <td>
-    <span class="span09"></span>
-  </td>
-  <td>
-    <span class="span08"></span>
-  </td>
+  <td><span class="span09"></span></td>
+  <td><span class="span08"></span></td>
 </tr>
 `,
 		},
@@ -648,7 +615,13 @@ func TestBuild(t *testing.T) {
 
 			elements := Build(tt.args.tokens, tt.args.num, tt.args.siblingCount)
 
-			elements.HTML(builder, counter, tt.args.mode, tt.args.indentation, tt.args.depth, tt.args.multiline, tt.args.tabStopWrapper)
+			MarkupRenderer{}.Render(builder, elements, counter, Options{
+				Mode:           tt.args.mode,
+				Indentation:    tt.args.indentation,
+				Depth:          tt.args.depth,
+				Multiline:      tt.args.multiline,
+				TabStopWrapper: tt.args.tabStopWrapper,
+			})
 
 			assert.Equal(t, tt.wantHTML, builder.String())
 		})
@@ -745,3 +718,81 @@ func TestCreateElem(t *testing.T) {
 		})
 	}
 }
+
+func TestBuild_Numbering(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		abbr string
+		want string
+	}{
+		{
+			name: "reversed group",
+			abbr: "(li.item$@-)*3",
+			want: `<li class="item3"></li><li class="item2"></li><li class="item1"></li>`,
+		},
+		{
+			name: "reversed group trickles down",
+			abbr: "ul>(li.item$@->span.x$@-)*3",
+			want: `<ul><li class="item3"><span class="x3"></span></li><li class="item2"><span class="x2"></span></li><li class="item1"><span class="x1"></span></li></ul>`,
+		},
+		{
+			name: "nearest repeated ancestor wins",
+			abbr: "(ul.list$>li.item$*2)*2",
+			want: `<ul class="list1"><li class="item1"></li><li class="item2"></li></ul><ul class="list2"><li class="item1"></li><li class="item2"></li></ul>`,
+		},
+		{
+			name: "outer group counter",
+			abbr: "(ul>li.item$~1*2)*2",
+			want: `<ul><li class="item1"></li><li class="item1"></li></ul><ul><li class="item2"></li><li class="item2"></li></ul>`,
+		},
+		{
+			name: "outer group counter in nested groups",
+			abbr: "(div>(p.x$~1+p.y$)*2)*2",
+			want: `<div><p class="x1"></p><p class="y1"></p><p class="x1"></p><p class="y2"></p></div><div><p class="x2"></p><p class="y1"></p><p class="x2"></p><p class="y2"></p></div>`,
+		},
+		{
+			name: "reversed outer counter",
+			abbr: "(tr>td.cell$@-~1*2)*2",
+			want: `<tr><td class="cell2"></td><td class="cell2"></td></tr><tr><td class="cell1"></td><td class="cell1"></td></tr>`,
+		},
+		{
+			name: "non-repeated elements are not levels",
+			abbr: "table>tr.row$*2>td.col$*2>span.r$~1",
+			want: `<table><tr class="row1"><td class="col1"><span class="r1"></span></td><td class="col2"><span class="r1"></span></td></tr><tr class="row2"><td class="col1"><span class="r2"></span></td><td class="col2"><span class="r2"></span></td></tr></table>`,
+		},
+		{
+			name: "attribute values",
+			abbr: "a[href=/page$ title=\"Page $$@-\"]*2",
+			want: `<a href="/page1" title="Page 02"></a><a href="/page2" title="Page 01"></a>`,
+		},
+		{
+			name: "text",
+			abbr: "li{Item $ costs \\$$}*2",
+			want: `<li>Item 1 costs $1</li><li>Item 2 costs $2</li>`,
+		},
+		{
+			name: "text and attribute values of nested groups",
+			abbr: "(ul>li[data-row=$~1]{$}*2)*2",
+			want: `<ul><li data-row="1">1</li><li data-row="1">2</li></ul><ul><li data-row="2">1</li><li data-row="2">2</li></ul>`,
+		},
+		{
+			name: "levels above the outermost repetition",
+			abbr: "ol*2>li.item$$~5*2",
+			want: `<ol><li class="item01"></li><li class="item01"></li></ol><ol><li class="item01"></li><li class="item01"></li></ol>`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Expand(tt.abbr, Options{})
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
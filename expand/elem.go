@@ -0,0 +1,160 @@
+package expand
+
+import "strings"
+
+type Elem struct {
+	Name string
+	// Raw is markup written as it is instead of an element, e.g. a doctype.
+	Raw          string
+	Classes      AttrValues
+	ID           *AttrValue
+	Attributes   AttrList
+	Text         *Text
+	Num          int
+	SiblingCount int
+	// Outer holds the repetitions of the repeated ancestors above the nearest one, outermost first.
+	Outer []Repetition
+	// WrapIndex is the line of wrapped content belonging to the element, 0 if it's not inside an implicit repeat.
+	WrapIndex int
+	Children  ElemList
+}
+
+func (e Elem) isEmptyTag() bool {
+	return len(e.Children) == 0 && e.Text.IsEmpty()
+}
+
+// selfCloses tells if e is written as a single tag, e.g. <br> in HTML or <item /> in XML, so it has no content.
+func (e Elem) selfCloses(mode Mode) bool {
+	if !e.isEmptyTag() {
+		return false
+	}
+
+	if mode.closesEmpty() {
+		return true
+	}
+
+	_, ok := shortHTMLTagNames[e.Name]
+
+	return ok && mode.isHTML()
+}
+
+func (e Elem) GetText(opts Options) string {
+	if e.Text == nil {
+		return ""
+	}
+
+	text := e.Text.GetValue(opts)
+	if len(e.Text.parts) > 0 {
+		text = e.number(e.Text.parts, opts, opts.escapeText, raw)
+	}
+
+	if e.Text.wrapped {
+		text += opts.wrap.get(e.WrapIndex)
+	}
+
+	return text
+}
+
+func (e Elem) Clone(num, siblingCount int) Elem {
+	return Elem{
+		Name:         e.Name,
+		Raw:          e.Raw,
+		ID:           e.ID.Clone(),
+		Classes:      e.Classes.Clone(),
+		Attributes:   e.Attributes.Clone(),
+		Text:         e.Text.Clone(),
+		Num:          num,
+		SiblingCount: siblingCount,
+		Outer:        e.Outer,
+		WrapIndex:    e.WrapIndex,
+		Children:     e.Children.Clone(num, siblingCount),
+	}
+}
+
+func (e Elem) GetNum() int {
+	return e.Num
+}
+
+// repetition returns the number and count of the repeated ancestor level steps above the nearest one, levels above
+// the outermost repetition count as a single element.
+func (e Elem) repetition(level int) (int, int) {
+	if level <= 0 {
+		return e.Num, e.SiblingCount
+	}
+
+	if level > len(e.Outer) {
+		return 1, 1
+	}
+
+	outer := e.Outer[len(e.Outer)-level]
+
+	return outer.Num, outer.Count
+}
+
+// number resolves the numbering markers and the wrapped content placeholders of an attribute value or text. Literal
+// parts are escaped by escapeLiteral, wrapped content by escapeContent.
+func (e Elem) number(parts AttrValues, opts Options, escapeLiteral, escapeContent func(string) string) string {
+	var builder strings.Builder
+
+	for _, part := range parts {
+		if part.Type == Content {
+			builder.WriteString(escapeLiteral(part.Value))
+			builder.WriteString(escapeContent(opts.wrap.get(e.WrapIndex)))
+
+			continue
+		}
+
+		builder.WriteString(escapeLiteral(part.getValue(e.repetition(part.Level))))
+	}
+
+	return builder.String()
+}
+
+// raw is the identity escaper, wrapped content is inserted into text as it is.
+func raw(value string) string {
+	return value
+}
+
+func (e Elem) GetID() string {
+	if e.ID == nil {
+		return ""
+	}
+
+	return e.ID.getValue(e.repetition(e.ID.Level))
+}
+
+func (e Elem) GetClass() string {
+	if len(e.Classes) == 0 {
+		return ""
+	}
+
+	classes := make([]string, 0, len(e.Classes))
+	for _, class := range e.Classes {
+		classes = append(classes, class.getValue(e.repetition(class.Level)))
+	}
+
+	return strings.Join(classes, " ")
+}
+
+// attrValue returns the escaped value of attr, numbered if it has numbering markers.
+func (e Elem) attrValue(attr *Attr, counter *Counter, opts Options) string {
+	value := attr.GetValue(counter, opts)
+	if len(attr.Parts) > 0 {
+		value = e.number(attr.Parts, opts, opts.escapeAttr, opts.escapeAttr)
+	}
+
+	return value
+}
+
+type ElemList []*Elem
+
+func (el ElemList) Clone(num, siblingCount int) ElemList {
+	newEl := make(ElemList, 0, len(el))
+
+	for _, e := range el {
+		clone := e.Clone(num, siblingCount)
+		newEl = append(newEl, &clone)
+	}
+
+	return newEl
+}
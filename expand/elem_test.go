@@ -1,4 +1,4 @@
-package main
+package expand
 
 import (
 	"strings"
@@ -7,7 +7,7 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-func TestElem_HTML(t *testing.T) {
+func TestMarkupRenderer_Render(t *testing.T) {
 	t.Parallel()
 
 	type args struct {
@@ -212,16 +212,8 @@ func TestElem_HTML(t *testing.T) {
 				tabStopWrapper: "",
 			},
 			want: `<div id="foo" style="background-color: red;" hello="bye" class="bar baz">
-  <p>
-    Hello, 
-    <span>
-      World
-    </span>
-    !
-  </p>
-  <p>
-    Aloha!
-  </p>
+  <p>Hello, <span>World</span>!</p>
+  <p>Aloha!</p>
 </div>
 `,
 		},
@@ -301,7 +293,13 @@ func TestElem_HTML(t *testing.T) {
 			builder := &strings.Builder{}
 			counter := NewCounter()
 
-			tt.sut.HTML(builder, counter, tt.args.mode, tt.args.indentation, tt.args.depth, tt.args.multiline, tt.args.tabStopWrapper)
+			MarkupRenderer{}.Render(builder, ElemList{&tt.sut}, counter, Options{
+				Mode:           tt.args.mode,
+				Indentation:    tt.args.indentation,
+				Depth:          tt.args.depth,
+				Multiline:      tt.args.multiline,
+				TabStopWrapper: tt.args.tabStopWrapper,
+			})
 
 			assert.Equal(t, tt.want, builder.String())
 		})
@@ -0,0 +1,252 @@
+package expand
+
+import (
+	"encoding/json"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Markers replacing the fields of Emmet snippets before they are tokenized. Like the tab stop markers, they are taken
+// from the Unicode private use area.
+const (
+	fieldStart = '\uE003'
+	fieldEnd   = '\uE004'
+)
+
+// emmetField matches the tab stops (${1}, ${2:placeholder}) and variables (${lang}) of Emmet snippets.
+//
+// nolint: gochecknoglobals
+var emmetField = regexp.MustCompile(`\$\{(\w+)(?::([^}]*))?\}`)
+
+// emmetVariables holds the default values of the variables used in Emmet snippets.
+//
+// nolint: gochecknoglobals
+var emmetVariables = map[string]string{
+	"lang":    "en",
+	"locale":  "en-US",
+	"charset": "UTF-8",
+}
+
+// emmetSyntaxes maps the Emmet syntaxes with a matching mode to it, snippets of other syntaxes are ignored.
+//
+// nolint: gochecknoglobals
+var emmetSyntaxes = map[string]Mode{
+	"html":  ModeHTML,
+	"xhtml": ModeHTML,
+	"xml":   ModeXML,
+	"xsl":   ModeXML,
+	"jsx":   ModeJSX,
+}
+
+// LoadEmmetSnippets reads snippets in the format of Emmet, either a flat object of HTML snippets like
+// https://github.com/emmetio/emmet/blob/master/src/snippets/html.json or an object of syntaxes like the snippets.json
+// of editors: {"html": {"snippets": {...}}}. Snippets which can not be used are skipped and returned as warnings.
+func LoadEmmetSnippets(path string) (Snippets, []error, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to read snippet file")
+	}
+
+	bySyntax := map[string]map[string]string{}
+
+	var flat map[string]string
+	if err := json.Unmarshal(data, &flat); err == nil {
+		bySyntax["html"] = flat
+	} else {
+		var config map[string]struct {
+			Snippets map[string]string `json:"snippets"`
+		}
+
+		if err := json.Unmarshal(data, &config); err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to parse snippet file %q", path)
+		}
+
+		for syntax, syntaxConfig := range config {
+			bySyntax[syntax] = syntaxConfig.Snippets
+		}
+	}
+
+	syntaxes := make([]string, 0, len(bySyntax))
+	for syntax := range bySyntax {
+		syntaxes = append(syntaxes, syntax)
+	}
+
+	sort.Strings(syntaxes)
+
+	snippets := Snippets{}
+
+	var warnings []error
+
+	for _, syntax := range syntaxes {
+		mode, ok := emmetSyntaxes[syntax]
+		if !ok {
+			continue
+		}
+
+		parsed, modeWarnings := parseEmmetSnippets(mode, bySyntax[syntax])
+		snippets = snippets.Merge(Snippets{mode: parsed})
+		warnings = append(warnings, modeWarnings...)
+	}
+
+	return snippets, warnings, nil
+}
+
+func parseEmmetSnippets(mode Mode, abbreviations map[string]string) (map[string]Snippet, []error) {
+	names := make([]string, 0, len(abbreviations))
+	for name := range abbreviations {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var (
+		snippets = map[string]Snippet{}
+		warnings []error
+		l        = NewLexer(mode)
+	)
+
+	for _, name := range names {
+		snippet, err := ParseSnippet(mode, abbreviations[name])
+		if err != nil {
+			warnings = append(warnings, errors.Wrapf(err, "snippet %q skipped", name))
+
+			continue
+		}
+
+		// keys can list aliases, e.g. "btn:s|button:s|button:submit"
+		for _, alias := range strings.Split(name, "|") {
+			if err := l.validSnippetName(alias); err != nil {
+				warnings = append(warnings, errors.Wrapf(ErrInvalidTagName, "snippet %q skipped", alias))
+
+				continue
+			}
+
+			snippets[alias] = snippet
+		}
+	}
+
+	return snippets, warnings
+}
+
+// ParseSnippet turns the abbreviation of an Emmet snippet into a Snippet, e.g. "a[href='http://${0}' target=_blank]".
+// Attribute values containing tab stops become default values. Snippets must have a single root element, the tab
+// stops of its children are replaced by their placeholders.
+func ParseSnippet(mode Mode, abbr string) (Snippet, error) {
+	root, children := splitChildren(abbr)
+	runes := []rune(emmetField.ReplaceAllStringFunc(root, markField))
+
+	tokens, _, err := NewLexer(mode).Tokenize(runes, false)
+	if err != nil {
+		return Snippet{}, errors.Wrap(err, ErrTokenizingMsg)
+	}
+
+	if len(tokens) != 1 {
+		return Snippet{}, errors.Wrap(ErrInvalidSnippet, "only snippets with a single root element are supported")
+	}
+
+	token, ok := tokens[0].(*TagToken)
+	if !ok || token.Repeat > 1 || token.ImplicitRepeat {
+		return Snippet{}, errors.Wrap(ErrInvalidSnippet, "only snippets with a single root element are supported")
+	}
+
+	snippet := Snippet{
+		Name:     token.Name,
+		Children: emmetField.ReplaceAllStringFunc(children, placeholder),
+	}
+
+	if snippet.Children != "" {
+		if _, _, err := NewLexer(mode).Tokenize([]rune(snippet.Children), false); err != nil {
+			return Snippet{}, errors.Wrap(err, ErrTokenizingMsg)
+		}
+	}
+
+	if token.ID != nil {
+		snippet.ID, _ = unmarkFields(token.ID.Value)
+	}
+
+	for _, class := range token.Classes {
+		value, _ := unmarkFields(class.Value)
+		snippet.Classes = append(snippet.Classes, value)
+	}
+
+	for _, attr := range token.Attributes {
+		prefix, value, suffix, hasField := splitField(attr.Value)
+
+		snippet.Attributes = append(snippet.Attributes, SnippetAttr{
+			Name:    attr.Name,
+			Value:   value,
+			Default: hasField && prefix+value+suffix != "",
+			Prefix:  prefix,
+			Suffix:  suffix,
+			Boolean: attr.IsBoolean(),
+		})
+	}
+
+	if token.Text != nil {
+		snippet.Text, _ = unmarkFields(token.Text.value)
+	}
+
+	return snippet, nil
+}
+
+// markField replaces a tab stop with its placeholder surrounded by field markers, and a variable with its value.
+func markField(field string) string {
+	match := emmetField.FindStringSubmatch(field)
+
+	if value, ok := emmetVariables[match[1]]; ok {
+		return value
+	}
+
+	return string(fieldStart) + match[2] + string(fieldEnd)
+}
+
+// placeholder replaces a tab stop with its placeholder, and a variable with its value.
+func placeholder(field string) string {
+	match := emmetField.FindStringSubmatch(field)
+
+	if value, ok := emmetVariables[match[1]]; ok {
+		return value
+	}
+
+	return match[2]
+}
+
+// splitChildren splits abbr at its first child operator outside of brackets, braces, parentheses and quotes.
+func splitChildren(abbr string) (string, string) {
+	runes := []rune(abbr)
+
+	idx, _, _ := findOutside(runes, dive)
+	if idx < 0 {
+		return abbr, ""
+	}
+
+	return string(runes[:idx]), string(runes[idx+1:])
+}
+
+// splitField splits value at its first field, e.g. "${1:style}.css" to "", "style" and ".css", the other fields
+// become text. It also tells if value had any fields.
+func splitField(value string) (string, string, string, bool) {
+	start := strings.IndexRune(value, fieldStart)
+	if start < 0 {
+		return "", value, "", false
+	}
+
+	end := start + strings.IndexRune(value[start:], fieldEnd)
+	prefix, _ := unmarkFields(value[:start])
+	suffix, _ := unmarkFields(value[end+len(string(fieldEnd)):])
+
+	return prefix, value[start+len(string(fieldStart)) : end], suffix, true
+}
+
+// unmarkFields removes the field markers from value and tells if there were any.
+func unmarkFields(value string) (string, bool) {
+	if !strings.ContainsAny(value, string([]rune{fieldStart, fieldEnd})) {
+		return value, false
+	}
+
+	return strings.NewReplacer(string(fieldStart), "", string(fieldEnd), "").Replace(value), true
+}
@@ -0,0 +1,257 @@
+package expand
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSnippet(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		abbr    string
+		want    Snippet
+		wantErr error
+	}{
+		{
+			name: "tab stops",
+			abbr: "a[href='http://${0}' target='_blank' rel='noopener noreferrer']",
+			want: Snippet{
+				Name: "a",
+				Attributes: []SnippetAttr{
+					{Name: "href", Default: true, Prefix: "http://"},
+					{Name: "target", Value: "_blank"},
+					{Name: "rel", Value: "noopener noreferrer"},
+				},
+			},
+		},
+		{
+			name: "placeholders and boolean attributes",
+			abbr: "select[name=${1} id=${2:select} disabled.]",
+			want: Snippet{
+				Name: "select",
+				Attributes: []SnippetAttr{
+					{Name: "name"},
+					{Name: "id", Value: "select", Default: true},
+					{Name: "disabled", Boolean: true},
+				},
+			},
+		},
+		{
+			name: "text around placeholders",
+			abbr: "link[rel=stylesheet href=${1:style}.css title='${2:a} and ${3:b}']",
+			want: Snippet{
+				Name: "link",
+				Attributes: []SnippetAttr{
+					{Name: "rel", Value: "stylesheet"},
+					{Name: "href", Value: "style", Default: true, Suffix: ".css"},
+					{Name: "title", Value: "a", Default: true, Suffix: " and b"},
+				},
+			},
+		},
+		{
+			name: "variables",
+			abbr: "meta[charset=${charset}]",
+			want: Snippet{
+				Name:       "meta",
+				Attributes: []SnippetAttr{{Name: "charset", Value: "UTF-8"}},
+			},
+		},
+		{
+			name: "id, classes and text",
+			abbr: "div#main.box.wide{${1:Hello}}",
+			want: Snippet{Name: "div", ID: "main", Classes: []string{"box", "wide"}, Text: "Hello"},
+		},
+		{
+			name: "subtree",
+			abbr: "ul.list>li[title='a > b']{${1:Item}}",
+			want: Snippet{Name: "ul", Classes: []string{"list"}, Children: "li[title='a > b']{Item}"},
+		},
+		{
+			name: "child operator in text",
+			abbr: "p{a > b}>span",
+			want: Snippet{Name: "p", Text: "a > b", Children: "span"},
+		},
+		{
+			name:    "invalid children",
+			abbr:    "ul>li.",
+			wantErr: ErrInputTooShort,
+		},
+		{
+			name:    "siblings",
+			abbr:    "dt+dd",
+			wantErr: ErrInvalidSnippet,
+		},
+		{
+			name:    "invalid abbreviation",
+			abbr:    "{<!-- ${0} -->}",
+			wantErr: ErrInputTooShort,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseSnippet(ModeHTML, tt.abbr)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLoadEmmetSnippets(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		content      string
+		want         Snippets
+		wantWarnings []string
+	}{
+		{
+			name:    "html.json",
+			content: `{"btn:x": "button[type=submit]", "ul+": "ul>li", "!!!": "{<!DOCTYPE html>}", "c": "{<!-- ${0} -->}"}`,
+			want: Snippets{
+				ModeHTML: {
+					"btn:x": {Name: "button", Attributes: []SnippetAttr{{Name: "type", Value: "submit"}}},
+					"ul+":   {Name: "ul", Children: "li"},
+				},
+			},
+			wantWarnings: []string{
+				`snippet "!!!" skipped: error tokenizing string: syntax error at line 1, column 1: input too short, expected tag name, found '{'`,
+				`snippet "c" skipped: error tokenizing string: syntax error at line 1, column 1: input too short, expected tag name, found '{'`,
+			},
+		},
+		{
+			name:    "aliases",
+			content: `{"btn:s|button:s|button:submit": "button[type=submit]", "opt|option": "option", "x|%y": "span"}`,
+			want: Snippets{
+				ModeHTML: {
+					"btn:s":         {Name: "button", Attributes: []SnippetAttr{{Name: "type", Value: "submit"}}},
+					"button:s":      {Name: "button", Attributes: []SnippetAttr{{Name: "type", Value: "submit"}}},
+					"button:submit": {Name: "button", Attributes: []SnippetAttr{{Name: "type", Value: "submit"}}},
+					"opt":           {Name: "option"},
+					"option":        {Name: "option"},
+					"x":             {Name: "span"},
+				},
+			},
+			wantWarnings: []string{`snippet "%y" skipped: invalid tag name`},
+		},
+		{
+			name: "snippets.json",
+			content: `{
+				"html": {"snippets": {"card": "div.card"}},
+				"xml": {"snippets": {"item": "entry[id=${1}]"}},
+				"css": {"snippets": {"fw": "font-weight"}}
+			}`,
+			want: Snippets{
+				ModeHTML: {"card": {Name: "div", Classes: []string{"card"}}},
+				ModeXML:  {"item": {Name: "entry", Attributes: []SnippetAttr{{Name: "id"}}}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "snippets.json")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o600))
+
+			got, gotWarnings, err := LoadEmmetSnippets(path)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, got)
+
+			var warnings []string
+			for _, warning := range gotWarnings {
+				warnings = append(warnings, warning.Error())
+			}
+
+			assert.Equal(t, tt.wantWarnings, warnings)
+		})
+	}
+}
+
+func TestExpand_EmmetSnippets(t *testing.T) {
+	t.Parallel()
+
+	snippets := Snippets{ModeHTML: map[string]Snippet{}}
+
+	for name, abbr := range map[string]string{
+		"inp":      "input[name=${1} id=${1}]",
+		"input:c":  "inp[type=checkbox checked]",
+		"a:blank":  "a[href='http://${0}' target='_blank' rel='noopener noreferrer']",
+		"tgl":      "button[aria-pressed=false toggle.]",
+		"link:css": "link[rel=stylesheet href=${1:style}.css]",
+	} {
+		snippet, err := ParseSnippet(ModeHTML, abbr)
+		require.NoError(t, err)
+
+		snippets[ModeHTML][name] = snippet
+	}
+
+	tests := []struct {
+		name string
+		abbr string
+		opts Options
+		want string
+	}{
+		{
+			name: "snippet referring to another one",
+			abbr: "input:c",
+			want: `<input type="checkbox" checked name="" id="">`,
+		},
+		{
+			name: "default value",
+			abbr: "a:blank",
+			want: `<a href="http://" target="_blank" rel="noopener noreferrer"></a>`,
+		},
+		{
+			name: "boolean marker",
+			abbr: "tgl",
+			want: `<button aria-pressed="false" toggle></button>`,
+		},
+		{
+			name: "tab stops of default values",
+			abbr: "a:blank",
+			opts: Options{TabStop: TabStopSnippet},
+			want: `<a href="http://${1}" target="_blank" rel="noopener noreferrer">${2}</a>`,
+		},
+		{
+			name: "text around a placeholder",
+			abbr: "link:css",
+			opts: Options{TabStop: TabStopSnippet},
+			want: `<link rel="stylesheet" href="${1:style}.css">`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tt.opts.Snippets = snippets
+
+			got, err := Expand(tt.abbr, tt.opts)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
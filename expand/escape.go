@@ -0,0 +1,82 @@
+package expand
+
+import (
+	"regexp"
+	"strings"
+)
+
+// characterReference matches named, decimal and hexadecimal character references, e.g. &nbsp; &#169; &#xA9;
+//
+// nolint: gochecknoglobals
+var characterReference = regexp.MustCompile(`^&(?:[a-zA-Z][a-zA-Z0-9]*|#[0-9]+|#[xX][0-9a-fA-F]+);`)
+
+// escape replaces the runes found in entities, ampersands starting a character reference are kept as they are, so
+// that p{&copy; 2024} works as expected.
+func escape(value string, entities map[rune]string) string {
+	var builder strings.Builder
+
+	for idx, r := range value {
+		entity, ok := entities[r]
+
+		switch {
+		case !ok:
+			builder.WriteRune(r)
+		case r == '&' && characterReference.MatchString(value[idx:]):
+			builder.WriteRune(r)
+		default:
+			builder.WriteString(entity)
+		}
+	}
+
+	return builder.String()
+}
+
+// escapeText escapes text content, XML also escapes '>' as it can't appear as part of "]]>", JSX escapes braces
+// which would start an expression.
+func (o Options) escapeText(value string) string {
+	if o.Raw {
+		return value
+	}
+
+	entities := map[rune]string{'&': "&amp;", '<': "&lt;"}
+	if o.Mode == ModeXML {
+		entities['>'] = "&gt;"
+	}
+
+	if o.Mode == ModeJSX {
+		entities['{'] = "&#123;"
+		entities['}'] = "&#125;"
+	}
+
+	if dialect, ok := templateDialects[o.Syntax]; ok {
+		return dialect.interpolation.Replace(escape(value, entities))
+	}
+
+	return escape(value, entities)
+}
+
+// escapeAttr escapes attribute values, only the quote character in use needs escaping. Attribute values of template
+// languages are string literals, escaped by backslashes.
+func (o Options) escapeAttr(value string) string {
+	if o.Raw {
+		return value
+	}
+
+	if dialect, ok := templateDialects[o.Syntax]; ok {
+		return dialect.escapeAttr(value, o.quote())
+	}
+
+	entities := map[rune]string{'&': "&amp;", '<': "&lt;", '"': "&quot;"}
+
+	if o.Quote == QuoteSingle {
+		delete(entities, '"')
+
+		// &apos; is not defined in HTML 4
+		entities['\''] = "&#39;"
+		if o.Mode == ModeXML {
+			entities['\''] = "&apos;"
+		}
+	}
+
+	return escape(value, entities)
+}
@@ -0,0 +1,115 @@
+package expand
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptions_escapeText(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		sut   Options
+		value string
+		want  string
+	}{
+		{
+			name:  "html",
+			sut:   Options{Mode: ModeHTML},
+			value: `a < b && "c" > d`,
+			want:  `a &lt; b &amp;&amp; "c" > d`,
+		},
+		{
+			name:  "xml",
+			sut:   Options{Mode: ModeXML},
+			value: `a < b && "c" > d`,
+			want:  `a &lt; b &amp;&amp; "c" &gt; d`,
+		},
+		{
+			name:  "character references are kept",
+			sut:   Options{Mode: ModeHTML},
+			value: `&copy; &#169; &#xA9; & &amp &#x;`,
+			want:  `&copy; &#169; &#xA9; &amp; &amp;amp &amp;#x;`,
+		},
+		{
+			name:  "pug interpolation",
+			sut:   Options{Mode: ModeHTML, Syntax: SyntaxPug},
+			value: `#{a} !{b} #[c] < #d`,
+			want:  `\#{a} \!{b} \#[c] &lt; #d`,
+		},
+		{
+			name:  "raw",
+			sut:   Options{Mode: ModeHTML, Raw: true},
+			value: `<%= x %>`,
+			want:  `<%= x %>`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, tt.sut.escapeText(tt.value))
+		})
+	}
+}
+
+func TestOptions_escapeAttr(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		sut   Options
+		value string
+		want  string
+	}{
+		{
+			name:  "double quotes",
+			sut:   Options{Mode: ModeHTML},
+			value: `"a" & 'b' <c>`,
+			want:  `&quot;a&quot; &amp; 'b' &lt;c>`,
+		},
+		{
+			name:  "single quotes in html",
+			sut:   Options{Mode: ModeHTML, Quote: QuoteSingle},
+			value: `"a" & 'b' <c>`,
+			want:  `"a" &amp; &#39;b&#39; &lt;c>`,
+		},
+		{
+			name:  "single quotes in xml",
+			sut:   Options{Mode: ModeXML, Quote: QuoteSingle},
+			value: `"a" & 'b' <c>`,
+			want:  `"a" &amp; &apos;b&apos; &lt;c>`,
+		},
+		{
+			name:  "pug",
+			sut:   Options{Mode: ModeHTML, Syntax: SyntaxPug},
+			value: `"a" & 'b' \c`,
+			want:  `\"a\" & 'b' \\c`,
+		},
+		{
+			name:  "pug with single quotes",
+			sut:   Options{Mode: ModeHTML, Syntax: SyntaxPug, Quote: QuoteSingle},
+			value: `"a" & 'b'`,
+			want:  `"a" & \'b\'`,
+		},
+		{
+			name:  "raw",
+			sut:   Options{Mode: ModeHTML, Raw: true},
+			value: `{{ printf "%s" .Name }}`,
+			want:  `{{ printf "%s" .Name }}`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, tt.sut.escapeAttr(tt.value))
+		})
+	}
+}
@@ -0,0 +1,97 @@
+// Package expand turns Emmet-like abbreviations into HTML, XML or HTMX markup.
+package expand
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	ErrTokenizingMsg     = "error tokenizing string"
+	ErrInvalidOptionsMsg = "invalid options"
+)
+
+// Expand runs the whole pipeline (tokenize, apply snippets, build, render) on abbr.
+func Expand(abbr string, opts Options) (string, error) {
+	if err := opts.Validate(); err != nil {
+		return "", errors.Wrap(err, ErrInvalidOptionsMsg)
+	}
+
+	got, _, err := render(abbr, opts.withDefaults())
+
+	return got, err
+}
+
+func render(abbr string, opts Options) (string, []*SyntaxError, error) {
+	l := NewLexer(opts.Mode)
+
+	// Split off filters, invalid ones are dropped when recovering
+	runes, filters, filterErr := l.SplitFilters([]rune(abbr))
+	if filterErr != nil && !opts.Recover {
+		return "", nil, errors.Wrap(filterErr, ErrTokenizingMsg)
+	}
+
+	opts.filters = filters
+
+	// Create raw tokens
+	tokens, warnings, err := tokenize(l, runes, opts.Recover)
+	if err != nil {
+		return "", warnings, errors.Wrap(err, ErrTokenizingMsg)
+	}
+
+	var syntaxErr *SyntaxError
+	if errors.As(filterErr, &syntaxErr) {
+		warnings = append(warnings, syntaxErr)
+	}
+
+	// Adjust tokens based on predefined rules
+	s := NewSnippeter(opts.Mode).SetSnippets(opts.Snippets).SetInlineElements(opts.InlineElements)
+	tokens = s.Walk(tokens...)
+
+	if opts.wrap != nil {
+		repeatImplicit(tokens, len(opts.wrap.lines))
+	}
+
+	// Convert tokens to HTML/XML elements
+	elemList := Build(tokens, 1, 1)
+
+	if opts.hasFilter(FilterBEM) {
+		elemList.bem("")
+	}
+
+	if opts.wrap != nil && !elemList.hasContent() {
+		elemList.insertContent()
+	}
+
+	// Render HTML/XML or a template syntax
+	builder := &strings.Builder{}
+	counter := NewCounter()
+
+	renderers[opts.Syntax].Render(builder, elemList, counter, opts)
+	rawResult := builder.String()
+
+	// Finalize response
+	return strings.Trim(rawResult, "\n\t\r "), warnings, nil
+}
+
+func tokenize(l *Lexer, runes []rune, recovering bool) ([]Token, []*SyntaxError, error) {
+	if recovering {
+		return l.TokenizeRecover(runes)
+	}
+
+	tokens, _, err := l.Tokenize(runes, false)
+
+	return tokens, nil, err
+}
+
+// ExpandRecover works like Expand with Options.Recover set, returning the issues found in abbr as warnings.
+func ExpandRecover(abbr string, opts Options) (string, []*SyntaxError, error) {
+	opts.Recover = true
+
+	if err := opts.Validate(); err != nil {
+		return "", nil, errors.Wrap(err, ErrInvalidOptionsMsg)
+	}
+
+	return render(abbr, opts.withDefaults())
+}
@@ -1,4 +1,4 @@
-package main
+package expand
 
 import (
 	"regexp"
@@ -9,7 +9,7 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-func TestXemmet(t *testing.T) {
+func TestExpand(t *testing.T) {
 	t.Parallel()
 
 	const validSnippet = "body[x-data=lorem3]>(table.table$@>(thead>tr.class$$@-3>th#th.col$@*4{lorem2})+(tbody>tr.row$@1*3>td*4{lorem10})+(tfoot>tr>td*4{lorem2}))*2"
@@ -17,7 +17,7 @@ func TestXemmet(t *testing.T) {
 	t.Run("generate multiline html", func(t *testing.T) {
 		t.Parallel()
 
-		got, gotErr := Xemmet(ModeHTML, validSnippet, "  ", 1, true, "$$$")
+		got, gotErr := Expand(validSnippet, Options{Mode: ModeHTML, Indentation: "  ", Depth: 1, Multiline: true, TabStopWrapper: "$$$"})
 		require.NoError(t, gotErr)
 
 		assert.NotEmpty(t, got)
@@ -30,8 +30,7 @@ func TestXemmet(t *testing.T) {
 		assert.NotContains(t, got, `    <table class="table3">`)
 
 		assert.Contains(t, got, `      <tr class="class03">`)
-		// TODO: Fix this trickling down issue
-		// assert.Contains(t, got, `      <tr class="class04">`)
+		assert.Contains(t, got, `      <tr class="class04">`)
 		assert.NotContains(t, got, `      <tr class="class05">`)
 
 		assert.Contains(t, got, `      <tr class="row1">`)
@@ -59,7 +58,7 @@ func TestXemmet(t *testing.T) {
 		to := gofakeit.Number(0, len(validSnippet)/2)
 		snippet := validSnippet[from : len(validSnippet)-to]
 
-		got, gotErr := Xemmet(ModeHTML, snippet, "  ", 1, true, "")
+		got, gotErr := Expand(snippet, Options{Mode: ModeHTML, Indentation: "  ", Depth: 1, Multiline: true})
 		require.Error(t, gotErr)
 		assert.Contains(t, gotErr.Error(), ErrTokenizingMsg)
 		assert.Empty(t, got)
@@ -90,10 +89,10 @@ func TestXemmet(t *testing.T) {
 				tabStopWrapper: "$",
 			},
 			want: `<collection foo="bar">
-      <item>$STOP1$</item>
-      <item>$STOP2$</item>
-      <item>$STOP3$</item>
-      <item bar="$STOP4$">$STOP5$</item>
+      <item />
+      <item />
+      <item />
+      <item bar="$STOP1$" />
     </collection>`,
 			wantErr: RequireNoError,
 		},
@@ -119,7 +118,6 @@ func TestXemmet(t *testing.T) {
     </div>`,
 			wantErr: RequireNoError,
 		},
-		// TODO: Fix BR tag
 		{
 			name: "very simple htmx - no tab stops, depth = 2, anchor used",
 			args: args{
@@ -138,10 +136,34 @@ func TestXemmet(t *testing.T) {
         <li id="item03" class="item"></li>
       </ul>
       <a href="https://" hx-get="https://" hx-trigger="click" hx-target="" hx-swap="innerHTML" class="button"></a>
-      <br></br>
+      <br>
     </div>`,
 			wantErr: RequireNoError,
 		},
+		{
+			name: "implicit tag names",
+			args: args{
+				mode:        ModeHTML,
+				snippet:     `.card>ul>.item*2>.label^^table>.row>.cell`,
+				indentation: "  ",
+			},
+			want: `<div class="card">
+  <ul>
+    <li class="item">
+      <div class="label"></div>
+    </li>
+    <li class="item">
+      <div class="label"></div>
+    </li>
+  </ul>
+  <table>
+    <tr class="row">
+      <td class="cell"></td>
+    </tr>
+  </table>
+</div>`,
+			wantErr: RequireNoError,
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -149,10 +171,98 @@ func TestXemmet(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			got, gotErr := Xemmet(tt.args.mode, tt.args.snippet, tt.args.indentation, tt.args.depth, true, tt.args.tabStopWrapper)
+			got, gotErr := Expand(tt.args.snippet, Options{
+				Mode:           tt.args.mode,
+				Indentation:    tt.args.indentation,
+				Depth:          tt.args.depth,
+				Multiline:      true,
+				TabStopWrapper: tt.args.tabStopWrapper,
+			})
 			tt.wantErr(t, gotErr)
 
 			assert.Equal(t, tt.want, got)
 		})
 	}
 }
+
+func TestExpand_JSX(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		abbr string
+		want string
+	}{
+		{
+			name: "className and htmlFor",
+			abbr: "div.field>label[for=email]{Email}",
+			want: `<div className="field"><label htmlFor="email">Email</label></div>`,
+		},
+		{
+			name: "empty elements are self-closed",
+			abbr: "div+br+Spinner",
+			want: `<div /><br /><Spinner />`,
+		},
+		{
+			name: "boolean attributes",
+			abbr: "input[disabled]",
+			want: `<input disabled={true} type="text" name="" />`,
+		},
+		{
+			name: "camelCase props",
+			abbr: "input[readonly autofocus tabindex=1 maxlength=5]+video[autoplay]",
+			want: `<input readOnly={true} autoFocus={true} tabIndex="1" maxLength="5" type="text" name="" />` +
+				`<video autoPlay={true} src="" />`,
+		},
+		{
+			name: "camelCase props of table cells and editable content",
+			abbr: "td[colspan=2 rowspan=3 contenteditable]+img[crossorigin=anonymous]",
+			want: `<td colSpan="2" rowSpan="3" contentEditable="" /><img crossOrigin="anonymous" src="" alt="" />`,
+		},
+		{
+			name: "expressions",
+			abbr: "button[onClick={() => save({id: 1})} title='{x}']{Save}",
+			want: `<button onClick={() => save({id: 1})} title="{x}">Save</button>`,
+		},
+		{
+			name: "numbering is not applied to expressions",
+			abbr: "li.item$*2>a[href={`/items/${id}`}]",
+			want: "<li className=\"item1\"><a href={`/items/${id}`} /></li>" +
+				"<li className=\"item2\"><a href={`/items/${id}`} /></li>",
+		},
+		{
+			name: "braces in text are escaped",
+			abbr: "p{if (x) {y}}",
+			want: `<p>if (x) &#123;y&#125;</p>`,
+		},
+		{
+			name: "html snippets",
+			abbr: "a:mail",
+			want: `<a href="mailto:" />`,
+		},
+		{
+			name: "comments",
+			abbr: "div.card|c",
+			want: `<div className="card" />{/* /.card */}`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Expand(tt.abbr, Options{Mode: ModeJSX})
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("no document snippets", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Expand("!", Options{Mode: ModeJSX})
+		require.ErrorIs(t, err, ErrInvalidTagName)
+	})
+}
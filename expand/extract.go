@@ -0,0 +1,164 @@
+package expand
+
+import (
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrAbbreviationNotFound = errors.New("abbreviation not found")
+	ErrUnbalancedBrackets   = errors.New("unbalanced brackets")
+	ErrInvalidCursor        = errors.New("cursor is out of range")
+)
+
+// nolint: gochecknoglobals
+var bracketPairs = map[rune]rune{
+	closingBracket:     openingBracket,
+	closingBrace:       openingBrace,
+	closingParenthesis: openingParenthesis,
+}
+
+// allowedAbbreviation lists the characters which may be part of an abbreviation outside brackets.
+func allowedAbbreviation(r rune) bool {
+	if unicode.IsLetter(r) || unicode.IsDigit(r) {
+		return true
+	}
+
+	switch r {
+	case plus, dive, ascend, atSign, dollarSign, hashSign, colon, dash, dotSign, star, tilde, '_', '!', '|', '%', '/':
+		return true
+	}
+
+	return false
+}
+
+// ExtractAbbreviation scans backwards from cursor (a rune offset in line) to find where the abbreviation before
+// the cursor starts. Brackets, braces, parentheses and quotes are balanced, while HTML tags right before the
+// abbreviation are skipped. It returns the abbreviation and the rune offset it starts at.
+func ExtractAbbreviation(line string, cursor int) (string, int, error) {
+	runes := []rune(line)
+
+	if cursor < 0 || cursor > len(runes) {
+		return "", 0, errors.Wrapf(ErrInvalidCursor, "cursor: %d, line length: %d", cursor, len(runes))
+	}
+
+	runes = runes[:cursor]
+
+	start, err := findAbbreviationStart(runes)
+	if err != nil {
+		return "", cursor, err
+	}
+
+	// abbreviations can not start with a directive
+	for start < cursor && (runes[start] == plus || runes[start] == dive || runes[start] == ascend) {
+		start++
+	}
+
+	if start == cursor {
+		return "", cursor, ErrAbbreviationNotFound
+	}
+
+	return string(runes[start:]), start, nil
+}
+
+// nolint: cyclop
+func findAbbreviationStart(runes []rune) (int, error) {
+	var (
+		pos          = len(runes)
+		openers      []rune
+		currentQuote rune
+	)
+
+	for pos > 0 {
+		r := runes[pos-1]
+
+		top := rune(0)
+		if len(openers) > 0 {
+			top = openers[len(openers)-1]
+		}
+
+		// the same rules as findOutside, backwards: quotes only count in brackets, braces hold any text
+		switch {
+		case currentQuote != 0:
+			if r == currentQuote {
+				currentQuote = 0
+			}
+
+		case top == openingBracket && (r == quote || r == singleQuote):
+			currentQuote = r
+
+		case top == openingBrace && r != openingBrace && r != closingBrace:
+			// everything but braces is text inside braces
+
+		case bracketPairs[r] != 0:
+			openers = append(openers, bracketPairs[r])
+
+		case r == openingBracket || r == openingBrace || r == openingParenthesis:
+			if len(openers) == 0 {
+				return pos, nil
+			}
+
+			if openers[len(openers)-1] != r {
+				return pos, ErrUnbalancedBrackets
+			}
+
+			openers = openers[:len(openers)-1]
+
+		case len(openers) > 0:
+			// everything is allowed inside brackets, braces and parentheses
+
+		case r == dive && isTagEnd(runes[:pos]):
+			return pos, nil
+
+		case !allowedAbbreviation(r):
+			return pos, nil
+		}
+
+		pos--
+	}
+
+	if len(openers) > 0 || currentQuote != 0 {
+		return pos, ErrUnbalancedBrackets
+	}
+
+	return pos, nil
+}
+
+// isTagEnd checks if runes end with an HTML/XML tag such as <div>, </p> or <a href="#">.
+func isTagEnd(runes []rune) bool {
+	var (
+		pos    = len(runes) - 2 // nolint: gomnd
+		quotes = 0
+	)
+
+	for ; pos >= 0; pos-- {
+		if runes[pos] == '<' {
+			break
+		}
+
+		if runes[pos] == quote {
+			quotes++
+		}
+
+		if runes[pos] == dive {
+			return false
+		}
+	}
+
+	// the closing angle bracket is inside an attribute value, e.g. <div class="ul>
+	if quotes%2 == 1 {
+		return false
+	}
+
+	if pos < 0 || pos+1 >= len(runes)-1 {
+		return false
+	}
+
+	name := runes[pos+1]
+	if name == '/' && pos+2 < len(runes)-1 {
+		name = runes[pos+2]
+	}
+
+	return unicode.IsLetter(name) || name == '!'
+}
@@ -0,0 +1,138 @@
+package expand
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractAbbreviation(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		line   string
+		cursor int
+	}
+	tests := []struct {
+		name      string
+		args      args
+		wantAbbr  string
+		wantStart int
+		wantErr   error
+	}{
+		{
+			name:      "whole line",
+			args:      args{line: "ul>li*3", cursor: 7},
+			wantAbbr:  "ul>li*3",
+			wantStart: 0,
+		},
+		{
+			name:      "after whitespace",
+			args:      args{line: "    ul>li", cursor: 9},
+			wantAbbr:  "ul>li",
+			wantStart: 4,
+		},
+		{
+			name:      "cursor in the middle",
+			args:      args{line: "foo ul>li bar", cursor: 9},
+			wantAbbr:  "ul>li",
+			wantStart: 4,
+		},
+		{
+			name:      "spaces and quotes in attributes and text",
+			args:      args{line: `text a[title="hello world" data-x='a]b']{click me}`, cursor: 50},
+			wantAbbr:  `a[title="hello world" data-x='a]b']{click me}`,
+			wantStart: 5,
+		},
+		{
+			name:      "quotes and brackets in text",
+			args:      args{line: "x p{it's [1]}", cursor: 13},
+			wantAbbr:  "p{it's [1]}",
+			wantStart: 2,
+		},
+		{
+			name:      "nested braces in text",
+			args:      args{line: "x p{{{ .Name }}}", cursor: 16},
+			wantAbbr:  "p{{{ .Name }}}",
+			wantStart: 2,
+		},
+		{
+			name:      "groups",
+			args:      args{line: "x (ul>li)*2+(p>span)", cursor: 20},
+			wantAbbr:  "(ul>li)*2+(p>span)",
+			wantStart: 2,
+		},
+		{
+			name:      "after html tag",
+			args:      args{line: `<div class="foo">ul>li`, cursor: 22},
+			wantAbbr:  "ul>li",
+			wantStart: 17,
+		},
+		{
+			name:      "after closing html tag",
+			args:      args{line: `<p></p>a:link`, cursor: 13},
+			wantAbbr:  "a:link",
+			wantStart: 7,
+		},
+		{
+			name:      "after attribute quote",
+			args:      args{line: `<div class="ul>li`, cursor: 17},
+			wantAbbr:  "ul>li",
+			wantStart: 12,
+		},
+		{
+			name:      "after unbalanced opening parenthesis",
+			args:      args{line: `render(p.x`, cursor: 10},
+			wantAbbr:  "p.x",
+			wantStart: 7,
+		},
+		{
+			name:      "leading directives are dropped",
+			args:      args{line: `>+p`, cursor: 3},
+			wantAbbr:  "p",
+			wantStart: 2,
+		},
+		{
+			name:      "multibyte characters",
+			args:      args{line: `árvíz p{tűrő}`, cursor: 13},
+			wantAbbr:  "p{tűrő}",
+			wantStart: 6,
+		},
+		{
+			name:      "unbalanced closing bracket",
+			args:      args{line: `p]`, cursor: 2},
+			wantStart: 2,
+			wantErr:   ErrUnbalancedBrackets,
+		},
+		{
+			name:      "mismatched brackets",
+			args:      args{line: `p[a}`, cursor: 4},
+			wantStart: 4,
+			wantErr:   ErrUnbalancedBrackets,
+		},
+		{
+			name:      "nothing before the cursor",
+			args:      args{line: `foo  `, cursor: 5},
+			wantStart: 5,
+			wantErr:   ErrAbbreviationNotFound,
+		},
+		{
+			name:    "cursor out of range",
+			args:    args{line: `foo`, cursor: 4},
+			wantErr: ErrInvalidCursor,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gotAbbr, gotStart, gotErr := ExtractAbbreviation(tt.args.line, tt.args.cursor)
+
+			assert.ErrorIs(t, gotErr, tt.wantErr)
+			assert.Equal(t, tt.wantAbbr, gotAbbr)
+			assert.Equal(t, tt.wantStart, gotStart)
+		})
+	}
+}
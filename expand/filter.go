@@ -0,0 +1,94 @@
+package expand
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var ErrUnknownFilter = errors.New("unknown filter")
+
+const pipe = '|'
+
+// Filter post-processes the output of an abbreviation, filters are appended to it separated by pipes, e.g. ul>li.item|c.
+type Filter string
+
+const (
+	// FilterComment adds a comment after elements with an id or classes, e.g. <!-- /#nav.main -->.
+	FilterComment Filter = "c"
+	// FilterBEM resolves the element (-, __) and modifier (_, --) shorthands of classes against the block of the
+	// parent, e.g. div.card>.-body_active becomes div.card>div.card__body.card__body--active.
+	FilterBEM Filter = "bem"
+)
+
+// nolint: gochecknoglobals
+var knownFilters = map[Filter]struct{}{
+	FilterComment: {},
+	FilterBEM:     {},
+}
+
+// SplitFilters splits the filters off the end of runes at the first pipe outside of brackets, braces, parentheses and
+// quotes. On failure the abbreviation and the filters before the invalid one are still returned, the error is a
+// SyntaxError.
+func (l *Lexer) SplitFilters(runes []rune) ([]rune, []Filter, error) {
+	start := filterStart(runes)
+	if start < 0 {
+		return runes, nil, nil
+	}
+
+	var filters []Filter
+
+	offset := start + 1
+
+	for _, name := range strings.Split(string(runes[offset:]), string(pipe)) {
+		filter := Filter(name)
+
+		if name == "" {
+			return runes[:start], filters, newSyntaxError(runes, offset, expected(ErrInputTooShort, "filter name"))
+		}
+
+		if _, ok := knownFilters[filter]; !ok {
+			return runes[:start], filters, newSyntaxError(runes, offset, expected(ErrUnknownFilter, "filter name"))
+		}
+
+		if !slices.Contains(filters, filter) {
+			filters = append(filters, filter)
+		}
+
+		offset += len([]rune(name)) + 1
+	}
+
+	return runes[:start], filters, nil
+}
+
+// filterStart returns the offset of the pipe starting the filters, -1 if there are none.
+func filterStart(runes []rune) int {
+	start, _, _ := findOutside(runes, pipe)
+
+	return start
+}
+
+func (o Options) hasFilter(filter Filter) bool {
+	return slices.Contains(o.filters, filter)
+}
+
+// commentText returns the text of the comment filter, e.g. /#main.wide, empty if it's not enabled or e has no id or
+// classes.
+func (e Elem) commentText(opts Options) string {
+	if !opts.hasFilter(FilterComment) || (e.ID == nil && len(e.Classes) == 0) {
+		return ""
+	}
+
+	text := "/"
+
+	if id := e.GetID(); id != "" {
+		text += "#" + id
+	}
+
+	for _, class := range e.Classes {
+		text += "." + class.getValue(e.repetition(class.Level))
+	}
+
+	return text
+}
@@ -0,0 +1,144 @@
+package expand
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLexer_SplitFilters(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		abbr        string
+		wantAbbr    string
+		wantFilters []Filter
+		wantErr     error
+	}{
+		{
+			name:     "no filters",
+			abbr:     "ul>li",
+			wantAbbr: "ul>li",
+		},
+		{
+			name:        "comment filter",
+			abbr:        "ul>li.item|c",
+			wantAbbr:    "ul>li.item",
+			wantFilters: []Filter{FilterComment},
+		},
+		{
+			name:        "multiple filters",
+			abbr:        "div.card>.-title|bem|c",
+			wantAbbr:    "div.card>.-title",
+			wantFilters: []Filter{FilterBEM, FilterComment},
+		},
+		{
+			name:        "duplicate filters",
+			abbr:        "p|c|c",
+			wantAbbr:    "p",
+			wantFilters: []Filter{FilterComment},
+		},
+		{
+			name:     "pipes in text and attribute values",
+			abbr:     `p[title="a|b" data-x='|']{c|d}`,
+			wantAbbr: `p[title="a|b" data-x='|']{c|d}`,
+		},
+		{
+			name:     "missing filter name",
+			abbr:     "p|",
+			wantAbbr: "p",
+			wantErr:  ErrInputTooShort,
+		},
+		{
+			name:        "unknown filter",
+			abbr:        "p|c|foo",
+			wantAbbr:    "p",
+			wantFilters: []Filter{FilterComment},
+			wantErr:     ErrUnknownFilter,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gotAbbr, gotFilters, err := NewLexer(ModeHTML).SplitFilters([]rune(tt.abbr))
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.wantAbbr, string(gotAbbr))
+			assert.Equal(t, tt.wantFilters, gotFilters)
+		})
+	}
+}
+
+func TestExpand_CommentFilter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		abbr string
+		opts Options
+		want string
+	}{
+		{
+			name: "id and classes",
+			abbr: "div#main.a.b|c",
+			opts: Options{Mode: ModeHTML},
+			want: `<div id="main" class="a b"></div><!-- /#main.a.b -->`,
+		},
+		{
+			name: "no comment without id or classes",
+			abbr: "ul>li|c",
+			opts: Options{Mode: ModeHTML},
+			want: `<ul><li></li></ul>`,
+		},
+		{
+			name: "numbered",
+			abbr: "p.item$*2|c",
+			opts: Options{Mode: ModeHTML},
+			want: `<p class="item1"></p><!-- /.item1 --><p class="item2"></p><!-- /.item2 -->`,
+		},
+		{
+			name: "short tag",
+			abbr: "br.x|c",
+			opts: Options{Mode: ModeHTML},
+			want: `<br class="x"><!-- /.x -->`,
+		},
+		{
+			name: "multiline",
+			abbr: "ul#nav>li.item|c",
+			opts: Options{Mode: ModeHTML, Indentation: "  ", Multiline: true},
+			want: "<ul id=\"nav\">\n" +
+				"  <li class=\"item\"></li>\n" +
+				"  <!-- /.item -->\n" +
+				"</ul>\n" +
+				"<!-- /#nav -->",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Expand(tt.abbr, tt.opts)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("unknown filter", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Expand("p|foo", Options{Mode: ModeHTML})
+		require.ErrorIs(t, err, ErrUnknownFilter)
+	})
+}
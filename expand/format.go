@@ -0,0 +1,68 @@
+package expand
+
+import (
+	"slices"
+	"strings"
+)
+
+// inlineElements lists the inline-level elements by mode, their parents keep them on the same line in multiline
+// output and their children without a tag name become span. XML has no inline elements.
+//
+// based on https://github.com/emmetio/emmet/blob/master/src/markup/format/html.ts
+//
+// nolint: gochecknoglobals
+var inlineElements = map[Mode][]string{
+	ModeHTML: htmlInlineElements,
+	ModeHTMX: htmlInlineElements,
+	ModeJSX:  htmlInlineElements,
+}
+
+// nolint: gochecknoglobals
+var htmlInlineElements = []string{
+	"a", "abbr", "acronym", "applet", "b", "basefont", "bdo", "big", "br", "button", "cite", "code", "del", "dfn",
+	"em", "font", "i", "iframe", "img", "input", "ins", "kbd", "label", "map", "object", "q", "s", "samp", "select",
+	"small", "span", "strike", "strong", "sub", "sup", "textarea", "tt", "u", "var",
+}
+
+// isInlineElement tells if name is an inline-level element, Options.InlineElements overrides the ones of the mode.
+func (o Options) isInlineElement(name string) bool {
+	return isInlineElement(o.Mode, o.InlineElements, name)
+}
+
+// isInlineElement tells if name is an inline-level element of mode, or one of override if it's not nil.
+func isInlineElement(mode Mode, override []string, name string) bool {
+	if override != nil {
+		return slices.Contains(override, name)
+	}
+
+	return slices.Contains(inlineElements[mode], name)
+}
+
+// isInline tells if e can stay on the line of its siblings: text, or an inline element with inline content only.
+func (e Elem) isInline(opts Options) bool {
+	if e.Raw != "" {
+		return false
+	}
+
+	if e.Name == "" {
+		return true
+	}
+
+	return opts.isInlineElement(e.Name) && (len(e.Children) == 0 || e.hasInlineContent(opts))
+}
+
+// hasInlineContent tells if all children of e are inline, or e has single-line text only, in which case they are
+// rendered on the line of e.
+func (e Elem) hasInlineContent(opts Options) bool {
+	if len(e.Children) == 0 {
+		return !e.Text.IsEmpty() && !strings.Contains(e.GetText(opts), "\n")
+	}
+
+	for _, child := range e.Children {
+		if !child.isInline(opts) {
+			return false
+		}
+	}
+
+	return true
+}
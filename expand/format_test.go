@@ -0,0 +1,122 @@
+package expand
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpand_InlineElements(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		abbr string
+		opts Options
+		want string
+	}{
+		{
+			name: "inline children stay on the line of their parent",
+			abbr: "p>span+a",
+			opts: Options{Mode: ModeHTML},
+			want: `<p><span></span><a href="#"></a></p>`,
+		},
+		{
+			name: "text and nested inline elements",
+			abbr: "ul>li*2>a>strong{Item $}",
+			opts: Options{Mode: ModeHTML},
+			want: "<ul>\n" +
+				"  <li><a href=\"#\"><strong>Item 1</strong></a></li>\n" +
+				"  <li><a href=\"#\"><strong>Item 2</strong></a></li>\n" +
+				"</ul>",
+		},
+		{
+			name: "text only",
+			abbr: "p{x}",
+			opts: Options{Mode: ModeHTML},
+			want: `<p>x</p>`,
+		},
+		{
+			name: "repeated text only",
+			abbr: "ul>li{x}*2",
+			opts: Options{Mode: ModeHTML},
+			want: "<ul>\n  <li>x</li>\n  <li>x</li>\n</ul>",
+		},
+		{
+			name: "text with block children",
+			abbr: "div{x}>p",
+			opts: Options{Mode: ModeHTML},
+			want: "<div>\n  x\n  <p></p>\n</div>",
+		},
+		{
+			name: "mixed children are broken into lines",
+			abbr: "div>span+p",
+			opts: Options{Mode: ModeHTML},
+			want: "<div>\n  <span></span>\n  <p></p>\n</div>",
+		},
+		{
+			name: "inline element with block children",
+			abbr: "a>div",
+			opts: Options{Mode: ModeHTML},
+			want: "<a href=\"#\">\n  <div></div>\n</a>",
+		},
+		{
+			name: "htmx",
+			abbr: "p>b",
+			opts: Options{Mode: ModeHTMX},
+			want: `<p><b></b></p>`,
+		},
+		{
+			name: "no inline elements in xml",
+			abbr: "p>b",
+			opts: Options{Mode: ModeXML},
+			want: "<p>\n  <b />\n</p>",
+		},
+		{
+			name: "custom inline elements",
+			abbr: "item>name+value",
+			opts: Options{Mode: ModeXML, InlineElements: []string{"name", "value"}},
+			want: `<item><name /><value /></item>`,
+		},
+		{
+			name: "inline elements disabled",
+			abbr: "p>span",
+			opts: Options{Mode: ModeHTML, InlineElements: []string{}},
+			want: "<p>\n  <span></span>\n</p>",
+		},
+		{
+			name: "omitted tag names become span in inline elements",
+			abbr: "label>.icon",
+			opts: Options{Mode: ModeHTML},
+			want: `<label for=""><span class="icon"></span></label>`,
+		},
+		{
+			name: "omitted tag names follow the custom inline elements",
+			abbr: "item>.icon",
+			opts: Options{Mode: ModeHTML, InlineElements: []string{"item", "span"}},
+			want: `<item><span class="icon"></span></item>`,
+		},
+		{
+			name: "comments go on their own line",
+			abbr: "p.intro>span|c",
+			opts: Options{Mode: ModeHTML},
+			want: "<p class=\"intro\"><span></span></p>\n<!-- /.intro -->",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tt.opts.Indentation = "  "
+			tt.opts.Multiline = true
+
+			got, err := Expand(tt.abbr, tt.opts)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
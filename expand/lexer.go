@@ -0,0 +1,968 @@
+package expand
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrInputTooShort           = errors.New("input too short")
+	ErrInvalidCharacter        = errors.New("invalid character")
+	ErrDirectiveClosingMissing = errors.New("directive closing missing")
+	ErrUnexpectedGroupClosing  = errors.New("unexpected group closing found")
+	ErrUnexpectedDirective     = errors.New("unexpected directive found")
+	ErrDuplicateID             = errors.New("duplicate id found")
+	ErrInvalidTagName          = errors.New("invalid tag name")
+)
+
+const (
+	plus               = '+'
+	dive               = '>'
+	ascend             = '^'
+	openingBracket     = '['
+	closingBracket     = ']'
+	openingParenthesis = '('
+	closingParenthesis = ')'
+	openingBrace       = '{'
+	closingBrace       = '}'
+	atSign             = '@'
+	dollarSign         = '$'
+	hashSign           = '#'
+	equalSign          = '='
+	colon              = ':'
+	dash               = '-'
+	dotSign            = '.'
+	space              = ' '
+	quote              = '"'
+	singleQuote        = '\''
+	star               = '*'
+	tilde              = '~'
+	exclamationMark    = '!'
+)
+
+type Action int32
+
+const (
+	Add        Action = plus
+	Dive       Action = dive
+	Ascend     Action = ascend
+	CloseGroup Action = closingParenthesis
+)
+
+func allowedClassName(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '_' || r == '-' || r >= '0' && r <= '9'
+}
+
+func startsImplicitTag(r rune) bool {
+	return r == dotSign || r == hashSign || r == openingBracket
+}
+
+// endsSubtreeSnippet tells if runes start with a plus which can't be a sibling operator, because it is followed by
+// the end of the input or an operator.
+func endsSubtreeSnippet(runes []rune) bool {
+	if len(runes) == 0 || runes[0] != plus {
+		return false
+	}
+
+	return len(runes) == 1 || strings.ContainsRune(">^)+*{", runes[1])
+}
+
+func allowedExclamationMark(r rune) bool {
+	return r == exclamationMark
+}
+
+func allowedNumbers(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+func allowedQuoteContent(r rune) bool {
+	return r != '"'
+}
+
+func allowedSingleQuoteContent(r rune) bool {
+	return r != singleQuote
+}
+
+func allowedUnquotedAttribute(r rune) bool {
+	return r != ' ' && r != closingBracket
+}
+
+type Lexer struct {
+	mode Mode
+}
+
+func NewLexer(mode Mode) *Lexer {
+	return &Lexer{
+		mode: mode,
+	}
+}
+
+func (l *Lexer) FindTokenValue(runes []rune, allowed func(r rune) bool) (string, int) {
+	var (
+		builder strings.Builder
+		length  int
+	)
+
+	for _, r := range runes { // nolint: varnamelen
+		if !allowed(r) {
+			break
+		}
+
+		length++
+
+		builder.WriteRune(r)
+	}
+
+	tokenValue := builder.String()
+
+	return tokenValue, length
+}
+
+// findOutside returns the offset of the first stop rune of runes outside of brackets, braces, parentheses and quotes,
+// -1 if there is none. Quotes only count in brackets, braces hold text with balanced braces. The
+// brackets, braces and parentheses left open before the stop rune or at the end of runes, and the open quote, are
+// returned too. A stop rune of 0 scans all of runes.
+// nolint: cyclop
+func findOutside(runes []rune, stop rune) (int, []rune, rune) {
+	var (
+		openers []rune
+		inQuote rune
+	)
+
+	for idx, r := range runes {
+		top := rune(0)
+		if len(openers) > 0 {
+			top = openers[len(openers)-1]
+		}
+
+		switch {
+		case inQuote != 0:
+			if r == inQuote {
+				inQuote = 0
+			}
+
+		case top == openingBrace:
+			switch r {
+			case openingBrace:
+				openers = append(openers, r)
+			case closingBrace:
+				openers = openers[:len(openers)-1]
+			}
+
+		case top == openingBracket:
+			switch r {
+			case quote, singleQuote:
+				inQuote = r
+			case closingBracket:
+				openers = openers[:len(openers)-1]
+			}
+
+		case r == openingBracket || r == openingBrace || r == openingParenthesis:
+			openers = append(openers, r)
+
+		case r == closingParenthesis && top == openingParenthesis:
+			openers = openers[:len(openers)-1]
+
+		case r == stop && stop != 0 && len(openers) == 0:
+			return idx, openers, inQuote
+		}
+	}
+
+	return -1, openers, inQuote
+}
+
+func (l *Lexer) FindNumbering(runes []rune) (int, bool, string, int, error) {
+	pos := 0
+
+	for _, r := range runes {
+		if r != dollarSign {
+			break
+		}
+
+		pos++
+	}
+
+	numbering := string(runes[:pos])
+
+	if len(runes) <= pos || runes[pos] != atSign {
+		return 1, false, numbering, pos, nil
+	}
+
+	pos++
+
+	// atSign can't be the last character in the numbering directive
+	if len(runes[pos:]) == 0 {
+		return 0, false, "", pos, expected(ErrInputTooShort, "number or '-' after '@'")
+	}
+
+	var (
+		reverse = false
+		start   = 1
+	)
+
+	if runes[pos] == dash {
+		pos++
+
+		reverse = true
+	}
+
+	str, length := l.FindTokenValue(runes[pos:], allowedNumbers)
+	if length == 0 {
+		// @ should not be defined on its own
+		if !reverse && numbering == "" {
+			return 0, false, "", pos, expected(ErrInputTooShort, "number after '@'")
+		}
+
+		return start, reverse, numbering, pos, nil
+	}
+
+	num, _ := strconv.Atoi(str)
+
+	start = num
+	pos += length
+
+	return start, reverse, numbering, pos, nil
+}
+
+// FindLevel finds the optional ~N suffix of a numbering, which refers to the counter of the repeated ancestor N levels
+// above the nearest one, e.g. ul*2>li.item$~1*3 numbers the items by their list.
+func (l *Lexer) FindLevel(runes []rune) (int, int, error) {
+	if len(runes) == 0 || runes[0] != tilde {
+		return 0, 0, nil
+	}
+
+	str, length := l.FindTokenValue(runes[1:], allowedNumbers)
+	if length == 0 {
+		return 0, 1, expected(ErrInputTooShort, "level after '~'")
+	}
+
+	level, _ := strconv.Atoi(str)
+
+	return level, length + 1, nil
+}
+
+// FindNumberedParts splits the value of an attribute or a text at its numbering markers, e.g. "item $$@-3 of $", and
+// at placeholders of wrapped content ($#). Escaped markers (\$) become literal dollar signs, so do invalid ones.
+// It returns nil if value has no markers.
+func (l *Lexer) FindNumberedParts(value string) AttrValues {
+	if !strings.ContainsRune(value, dollarSign) {
+		return nil
+	}
+
+	var (
+		parts   AttrValues
+		literal strings.Builder
+		runes   = []rune(value)
+		pos     = 0
+	)
+
+	for pos < len(runes) {
+		if runes[pos] == '\\' && pos+1 < len(runes) && runes[pos+1] == dollarSign {
+			literal.WriteRune(dollarSign)
+			pos += 2
+
+			continue
+		}
+
+		if runes[pos] != dollarSign {
+			literal.WriteRune(runes[pos])
+			pos++
+
+			continue
+		}
+
+		if pos+1 < len(runes) && runes[pos+1] == hashSign {
+			parts = append(parts, NewContent(literal.String()))
+			literal.Reset()
+			pos += 2
+
+			continue
+		}
+
+		start, reverse, numbering, numLength, err := l.FindNumbering(runes[pos:])
+		if err != nil {
+			literal.WriteRune(dollarSign)
+			pos++
+
+			continue
+		}
+
+		part := NewPart(literal.String()).SetNumbering(numbering).SetStart(start).SetReverse(reverse)
+		pos += numLength
+
+		if level, levelLength, err := l.FindLevel(runes[pos:]); err == nil {
+			part.SetLevel(level)
+			pos += levelLength
+		}
+
+		parts = append(parts, part)
+		literal.Reset()
+	}
+
+	if literal.Len() > 0 || len(parts) == 0 {
+		parts = append(parts, NewPart(literal.String()))
+	}
+
+	return parts
+}
+
+func (l *Lexer) FindClassOrIDToken(runes []rune) (*AttrValue, int, error) {
+	what := "class name"
+	if len(runes) > 0 && runes[0] == hashSign {
+		what = "id"
+	}
+
+	if len(runes) < 2 { // nolint: gomnd
+		return nil, len(runes), expected(ErrInputTooShort, what)
+	}
+
+	if runes[0] != dotSign && runes[0] != hashSign {
+		return nil, 0, expected(ErrInvalidCharacter, "'.' or '#'")
+	}
+
+	value, length := l.FindTokenValue(runes[1:], allowedClassName)
+
+	if length == 0 {
+		return nil, 1, expected(ErrInputTooShort, what)
+	}
+
+	var token *AttrValue
+
+	if runes[0] == hashSign {
+		token = NewID(value)
+	} else {
+		token = NewClass(value)
+	}
+
+	pos := length + 1
+
+	if pos < len(runes) && (runes[pos] == dollarSign || runes[pos] == atSign) {
+		start, reverse, numbering, numLength, err := l.FindNumbering(runes[pos:])
+		if err != nil {
+			return nil, pos + numLength, err
+		}
+
+		token.SetNumbering(numbering).SetStart(start).SetReverse(reverse)
+
+		pos += numLength
+
+		level, levelLength, err := l.FindLevel(runes[pos:])
+		if err != nil {
+			return nil, pos + levelLength, err
+		}
+
+		token.SetLevel(level)
+
+		pos += levelLength
+	}
+
+	return token, pos, nil
+}
+
+func (l *Lexer) FindAttribute(runes []rune) (string, string, bool, int, error) {
+	name, length := l.FindTokenValue(runes, allowedClassName)
+
+	// Attribute name can't be empty
+	if length == 0 {
+		return "", "", false, 0, expected(ErrInputTooShort, "attribute name")
+	}
+
+	// No equal sign after attribute name
+	if len(runes[length:]) == 0 || runes[length] == ' ' || runes[length] == closingBracket {
+		return name, "", false, length, nil
+	}
+
+	// Boolean attribute, e.g. [disabled.]
+	if runes[length] == dotSign && (len(runes[length+1:]) == 0 || runes[length+1] == ' ' || runes[length+1] == closingBracket) {
+		return name, "", false, length + 1, nil
+	}
+
+	// Equal sign is expected after attribute name for attributes with a Value
+	if runes[length] != equalSign {
+		return "", "", false, length, expected(ErrInvalidCharacter, "'=', '.', ' ' or ']'")
+	}
+
+	pos := length + 1
+
+	if len(runes[pos:]) == 0 || runes[pos] == space {
+		return name, "", true, pos, nil
+	}
+
+	if runes[pos] == openingBrace && l.mode == ModeJSX {
+		value, valueLength, err := l.FindExpression(runes[pos:])
+
+		return name, value, true, pos + valueLength, err
+	}
+
+	if runes[pos] == quote || runes[pos] == singleQuote {
+		allowed := allowedQuoteContent
+		if runes[pos] == singleQuote {
+			allowed = allowedSingleQuoteContent
+		}
+
+		value, valueLength := l.FindTokenValue(runes[pos+1:], allowed)
+
+		end := pos + valueLength + 1
+		if end >= len(runes) || runes[end] != runes[pos] {
+			return "", "", false, len(runes), expected(ErrDirectiveClosingMissing, fmt.Sprintf("%q", runes[pos]))
+		}
+
+		return name, value, true, end + 1, nil
+	}
+
+	value, valueLength := l.FindTokenValue(runes[pos:], allowedUnquotedAttribute)
+
+	return name, value, true, pos + valueLength, nil
+}
+
+// FindExpression finds a JSX expression in braces, e.g. {user.name}, nested braces must be balanced. The expression
+// is returned without the outer braces.
+func (l *Lexer) FindExpression(runes []rune) (string, int, error) {
+	depth := 0
+
+	for idx, r := range runes {
+		switch r {
+		case openingBrace:
+			depth++
+
+		case closingBrace:
+			depth--
+
+			if depth == 0 {
+				return string(runes[1:idx]), idx + 1, nil
+			}
+		}
+	}
+
+	return "", len(runes), expected(ErrDirectiveClosingMissing, "'}'")
+}
+
+func (l *Lexer) FindAttributeTokens(runes []rune) (AttrList, int, error) {
+	if len(runes) > 0 && runes[0] != openingBracket {
+		return nil, 0, expected(ErrInvalidCharacter, "'['")
+	}
+
+	if len(runes) < 2 { // nolint:gomnd
+		return nil, len(runes), expected(ErrInputTooShort, "attribute name or ']'")
+	}
+
+	var attributes AttrList
+
+	pos := 1
+
+	for {
+		if runes[pos] == closingBracket {
+			return attributes, pos + 1, nil
+		}
+
+		name, value, hasEqualSign, length, err := l.FindAttribute(runes[pos:])
+		valueStart := pos + len([]rune(name)) + 1
+		pos += length
+
+		if err != nil {
+			return nil, pos, err
+		}
+
+		if l.mode == ModeJSX && hasEqualSign && valueStart < len(runes) && runes[valueStart] == openingBrace {
+			attributes = append(attributes, NewAttr(name, value).SetExpression())
+		} else {
+			attributes = append(attributes, NewAttr(name, value).SetParts(l.FindNumberedParts(value)))
+		}
+
+		if !hasEqualSign {
+			attributes[len(attributes)-1].HasNoEqualSign()
+		}
+
+		// only the boolean marker can end an attribute without a value in a dot
+		if !hasEqualSign && runes[pos-1] == dotSign {
+			attributes[len(attributes)-1].SetBoolean()
+		}
+
+		for _, ch := range runes[pos:] {
+			if ch != ' ' {
+				break
+			}
+
+			pos++
+		}
+
+		if len(runes[pos:]) == 0 {
+			break
+		}
+	}
+
+	return nil, pos, expected(ErrDirectiveClosingMissing, "']'")
+}
+
+func (l *Lexer) FindClassToken(runes []rune) (*AttrValue, int, error) {
+	classToken, length, err := l.FindClassOrIDToken(runes)
+	if err != nil {
+		return nil, length, err
+	}
+
+	return classToken, length, nil
+}
+
+func (l *Lexer) FindIDToken(runes []rune) (*AttrValue, int, error) {
+	idToken, length, err := l.FindClassOrIDToken(runes)
+	if err != nil {
+		return nil, length, err
+	}
+
+	return idToken, length, nil
+}
+
+func (l *Lexer) FindAllAttributeTokens(token *TagToken, runes []rune) (int, error) {
+	if len(runes) == 0 {
+		return 0, nil
+	}
+
+	pos := 0
+
+	for {
+		if len(runes) <= pos {
+			break
+		}
+
+		switch runes[pos] {
+		case openingBracket:
+			currentTokens, attrLength, err := l.FindAttributeTokens(runes[pos:])
+			if err != nil {
+				return pos + attrLength, err
+			}
+
+			token.Attributes = append(token.Attributes, currentTokens...)
+			pos += attrLength
+
+		case dotSign:
+			currentToken, attrLength, err := l.FindClassToken(runes[pos:])
+			if err != nil {
+				return pos + attrLength, err
+			}
+
+			token.Classes = append(token.Classes, currentToken)
+			pos += attrLength
+
+		case hashSign:
+			if token.ID != nil {
+				return pos, expected(ErrDuplicateID, "a single id")
+			}
+
+			currentToken, attrLength, err := l.FindIDToken(runes[pos:])
+			if err != nil {
+				return pos + attrLength, err
+			}
+
+			token.ID = currentToken
+			pos += attrLength
+		default:
+			return pos, nil
+		}
+	}
+
+	return pos, nil
+}
+
+// FindRepeat finds the repeat count after '*'. A repeat of 0 means the count was omitted (e.g. li*), such elements are
+// repeated once per line of content when wrapping, see Wrap.
+func (l *Lexer) FindRepeat(runes []rune) (int, int, error) {
+	if len(runes) == 0 || runes[0] != star {
+		return 1, 0, nil
+	}
+
+	repeatStr, numLength := l.FindTokenValue(runes[1:], allowedNumbers)
+	if numLength == 0 {
+		return 0, 1, nil
+	}
+
+	repeat, err := strconv.Atoi(repeatStr)
+	if err != nil {
+		return 0, 1, expected(ErrInvalidCharacter, "repeat count after '*'")
+	}
+
+	if repeat < 1 {
+		repeat = 1
+	}
+
+	return repeat, numLength + 1, nil
+}
+
+// NextTextToken finds a text in braces, nested braces must be balanced, e.g. {{{ .Name }}}.
+func (l *Lexer) NextTextToken(runes []rune) (*Text, int, error) {
+	if len(runes) == 0 || runes[0] != openingBrace {
+		return nil, 0, nil
+	}
+
+	value, length, err := l.FindExpression(runes)
+	if err != nil {
+		return nil, length, err
+	}
+
+	if value == "" {
+		return nil, length, nil
+	}
+
+	return NewText(value).SetParts(l.FindNumberedParts(value)), length, nil
+}
+
+func (l *Lexer) NextTagToken(runes []rune) (*TagToken, int, error) {
+	if len(runes) == 0 {
+		return nil, 0, expected(ErrInputTooShort, "tag name")
+	}
+
+	value, length := l.FindTokenValue(runes, allowedTagName)
+
+	// document snippets, e.g. !
+	if length == 0 && runes[0] == exclamationMark {
+		value, length = l.FindTokenValue(runes, allowedExclamationMark)
+	}
+
+	// subjects starting with a class, an id or attributes get an implicit tag name later, see Snippeter.Walk
+	if length == 0 && !startsImplicitTag(runes[0]) {
+		return nil, 0, expected(ErrInputTooShort, "tag name")
+	}
+
+	if length > 0 {
+		if invalidPos, err := l.validTagName(runes[:length]); err != nil {
+			return nil, invalidPos, err
+		}
+	}
+
+	token := NewTagToken(value, 1)
+	pos := length
+
+	classLength, err := l.FindAllAttributeTokens(token, runes[pos:])
+	if err != nil {
+		return nil, pos + classLength, err
+	}
+
+	pos += classLength
+
+	// a plus directly followed by an operator marks a snippet expanding to a subtree, e.g. ul+ or table.data+>tr.head
+	if value != "" && endsSubtreeSnippet(runes[pos:]) {
+		token.SetName(value + string(plus))
+		pos++
+	}
+
+	repeat, repeatLength, err := l.FindRepeat(runes[pos:])
+	if err != nil {
+		return nil, pos + repeatLength, err
+	}
+
+	if repeatLength > 0 {
+		pos += repeatLength
+		token.SetRepeat(repeat)
+	}
+
+	textToken, textLength, err := l.NextTextToken(runes[pos:])
+	if err != nil {
+		return nil, pos + textLength, err
+	}
+
+	token.Text = textToken
+	pos += textLength
+
+	// the repeat may also follow the text, e.g. li{Item $}*5
+	if repeatLength == 0 && textLength > 0 {
+		repeat, repeatLength, err = l.FindRepeat(runes[pos:])
+		if err != nil {
+			return nil, pos + repeatLength, err
+		}
+
+		pos += repeatLength
+		token.SetRepeat(repeat)
+	}
+
+	return token, pos, nil
+}
+
+// nolint: ireturn
+func (l *Lexer) NextSubjectToken(runes []rune) (Token, int, error) {
+	if len(runes) == 0 {
+		return nil, 0, expected(ErrInputTooShort, "tag name or '('")
+	}
+
+	if runes[0] == openingParenthesis {
+		tokens, length, err := l.tokenize(runes[1:], true)
+		pos := length + 1
+
+		if err != nil {
+			return nil, pos, err
+		}
+
+		if runes[pos-1] != closingParenthesis {
+			return nil, pos, expected(ErrDirectiveClosingMissing, "')'")
+		}
+
+		repeat, numLength, err := l.FindRepeat(runes[pos:])
+		if err != nil {
+			return nil, pos + numLength, err
+		}
+
+		return NewGroupToken(1, tokens...).SetRepeat(repeat), pos + numLength, nil
+	}
+
+	return l.NextTagToken(runes)
+}
+
+// nolint: cyclop
+func (l *Lexer) NextDirectiveToken(runes []rune) (*DirectiveToken, int, error) {
+	if len(runes) == 0 {
+		return nil, 0, nil
+	}
+
+	var token *DirectiveToken
+
+	for _, curRune := range runes {
+		switch {
+		case curRune == plus || curRune == dive:
+			if token != nil {
+				return nil, 1, expected(ErrInvalidCharacter, "tag name or '('")
+			}
+
+			token = NewDirectiveToken(Action(curRune), 1)
+
+		case curRune == ascend:
+			if token == nil {
+				token = NewDirectiveToken(Action(curRune), 0)
+			}
+
+			if token.Name != Action(curRune) {
+				return nil, token.Repeat, expected(ErrInvalidCharacter, "tag name or '('")
+			}
+
+			token.Repeat++
+
+		case curRune == closingParenthesis:
+			if token != nil {
+				return nil, 1, expected(ErrInvalidCharacter, "tag name or '('")
+			}
+
+			return NewDirectiveToken(CloseGroup, 1), 1, nil
+
+		default:
+			goto breakout
+		}
+	}
+
+breakout:
+	if token == nil {
+		return nil, 0, expected(ErrInvalidCharacter, "'+', '>', '^' or ')'")
+	}
+
+	return token, token.Repeat, nil
+}
+
+// Tokenize turns runes into a token tree. On failure the returned error is a *SyntaxError and the returned
+// position is the absolute offset of the failure.
+func (l *Lexer) Tokenize(runes []rune, inGroup bool) ([]Token, int, error) {
+	tokens, pos, err := l.tokenize(runes, inGroup)
+	if err != nil {
+		return nil, pos, newSyntaxError(runes, pos, err)
+	}
+
+	return tokens, pos, nil
+}
+
+func (l *Lexer) tokenize(runes []rune, inGroup bool) ([]Token, int, error) {
+	var (
+		tokens    []Token
+		lastToken Token
+		directive *DirectiveToken
+	)
+
+	pos := 0
+
+	subject, length, err := l.NextSubjectToken(runes[pos:])
+	pos += length
+	lastToken = subject
+
+	if err != nil {
+		return nil, pos, err
+	}
+
+	tokens = append(tokens, subject)
+
+	for pos < len(runes) {
+		if pos == len(runes) {
+			break
+		}
+
+		directive, length, err = l.NextDirectiveToken(runes[pos:])
+		pos += length
+
+		if err != nil {
+			return nil, pos, err
+		}
+
+		if directive.Name == CloseGroup {
+			if inGroup {
+				return tokens, pos, nil
+			}
+
+			return nil, pos - 1, expected(ErrUnexpectedGroupClosing, "'+', '>' or '^'")
+		}
+
+		subject, length, err = l.NextSubjectToken(runes[pos:])
+		pos += length
+
+		if err != nil {
+			return nil, pos, err
+		}
+
+		tokens = l.act(directive, subject, tokens, lastToken)
+
+		lastToken = subject
+	}
+
+	return tokens, pos, nil
+}
+
+func (l *Lexer) act(directive *DirectiveToken, subject Token, tokens []Token, lastToken Token) []Token {
+	switch directive.Name {
+	case Add:
+		parent := lastToken.GetParent()
+		if parent == nil {
+			tokens = append(tokens, subject)
+		} else {
+			parent.AddChildren(subject)
+		}
+
+	case Dive:
+		lastToken.AddChildren(subject)
+
+	case Ascend:
+		parent := lastToken
+
+		for i := 0; i < directive.Repeat+1; i++ {
+			parent = parent.GetParent()
+
+			// Traversing too high is ignored, in line with Emmet
+			if parent == nil {
+				break
+			}
+		}
+
+		if parent == nil {
+			tokens = append(tokens, subject)
+		} else {
+			parent.AddChildren(subject)
+		}
+
+	case CloseGroup:
+		panic("logic error")
+
+	default:
+		panic("logic error")
+	}
+
+	return tokens
+}
+
+// SyntaxError describes where and why tokenizing an abbreviation failed. Offset is an absolute rune offset, Line and
+// Column are 1-based.
+type SyntaxError struct {
+	Offset   int
+	Line     int
+	Column   int
+	Expected string
+	Found    string
+	Err      error
+}
+
+const endOfInput = "end of input"
+
+// expected attaches a description of what the lexer expected to a sentinel error, the position is added by Tokenize.
+func expected(err error, what string) error {
+	return &SyntaxError{
+		Expected: what,
+		Err:      err,
+	}
+}
+
+func newSyntaxError(runes []rune, offset int, err error) *SyntaxError {
+	syntaxErr := &SyntaxError{Err: err}
+
+	var inner *SyntaxError
+	if errors.As(err, &inner) {
+		syntaxErr.Expected = inner.Expected
+		syntaxErr.Err = inner.Err
+	}
+
+	syntaxErr.Offset = offset
+	syntaxErr.Line, syntaxErr.Column = lineAndColumn(runes, offset)
+
+	syntaxErr.Found = endOfInput
+	if offset < len(runes) {
+		syntaxErr.Found = strconv.QuoteRune(runes[offset])
+	}
+
+	return syntaxErr
+}
+
+func lineAndColumn(runes []rune, offset int) (int, int) {
+	line, column := 1, 1
+
+	for _, r := range runes[:min(offset, len(runes))] {
+		column++
+
+		if r == '\n' {
+			line++
+			column = 1
+		}
+	}
+
+	return line, column
+}
+
+func (e *SyntaxError) Error() string {
+	msg := fmt.Sprintf("syntax error at line %d, column %d: %s", e.Line, e.Column, e.Err)
+
+	if e.Expected != "" {
+		msg += ", expected " + e.Expected
+	}
+
+	if e.Found != "" {
+		msg += ", found " + e.Found
+	}
+
+	return msg
+}
+
+func (e *SyntaxError) Unwrap() error {
+	return e.Err
+}
+
+// Shift moves the error by delta runes, which is useful if the abbreviation was taken from a longer input.
+func (e *SyntaxError) Shift(input string, delta int) *SyntaxError {
+	return newSyntaxError([]rune(input), e.Offset+delta, &SyntaxError{Expected: e.Expected, Err: e.Err})
+}
+
+// Caret returns the line of input containing the error and a caret pointing at the failing character below it.
+func (e *SyntaxError) Caret(input string) string {
+	lines := strings.Split(input, "\n")
+	if e.Line < 1 || e.Line > len(lines) {
+		return ""
+	}
+
+	line := []rune(lines[e.Line-1])
+
+	// keep tabs to align the caret with the failing character
+	padding := make([]rune, 0, e.Column-1)
+	for _, r := range line[:min(e.Column-1, len(line))] {
+		if r == '\t' {
+			padding = append(padding, r)
+		} else {
+			padding = append(padding, ' ')
+		}
+	}
+
+	for len(padding) < e.Column-1 {
+		padding = append(padding, ' ')
+	}
+
+	return string(line) + "\n" + string(padding) + "^"
+}
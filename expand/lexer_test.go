@@ -1,9 +1,10 @@
-package main
+package expand
 
 import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestLexer_FindTokenValue(t *testing.T) {
@@ -47,6 +48,71 @@ func TestLexer_FindTokenValue(t *testing.T) {
 	}
 }
 
+func TestFindOutside(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		s           string
+		stop        rune
+		wantIdx     int
+		wantOpeners []rune
+		wantQuote   rune
+	}{
+		{
+			name:    "found",
+			s:       "ul>li|c",
+			stop:    '|',
+			wantIdx: 5,
+		},
+		{
+			name:    "not found",
+			s:       "ul>li",
+			stop:    '|',
+			wantIdx: -1,
+		},
+		{
+			name:    "skips brackets, braces and parentheses",
+			s:       "a[x='|]' y=\"|\"]+p{a|b}+(a|b)|c",
+			stop:    '|',
+			wantIdx: 28,
+		},
+		{
+			name:    "quotes only count in brackets",
+			s:       "p{it's}>a",
+			stop:    '>',
+			wantIdx: 7,
+		},
+		{
+			name:    "nested braces in text",
+			s:       "p{{{ .Name }}|}|c",
+			stop:    '|',
+			wantIdx: 15,
+		},
+		{
+			name:        "unterminated",
+			s:           "(ul>li[title='a",
+			stop:        0,
+			wantIdx:     -1,
+			wantOpeners: []rune{'(', '['},
+			wantQuote:   '\'',
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gotIdx, gotOpeners, gotQuote := findOutside([]rune(tt.s), tt.stop)
+
+			assert.Equal(t, tt.wantIdx, gotIdx)
+			assert.Equal(t, string(tt.wantOpeners), string(gotOpeners))
+			assert.Equal(t, tt.wantQuote, gotQuote)
+		})
+	}
+}
+
 func TestLexer_FindNumbering(t *testing.T) {
 	t.Parallel()
 
@@ -213,6 +279,67 @@ func TestLexer_FindNumbering(t *testing.T) {
 	}
 }
 
+func TestLexer_FindNumberedParts(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		value string
+		want  AttrValues
+	}{
+		{
+			name:  "no markers",
+			value: "foo",
+			want:  nil,
+		},
+		{
+			name:  "single marker",
+			value: "/page$",
+			want:  AttrValues{NewPart("/page").SetNumbering("$")},
+		},
+		{
+			name:  "multiple markers with modifiers",
+			value: "item $$@-3 of $~1!",
+			want: AttrValues{
+				NewPart("item ").SetNumbering("$$").SetStart(3).SetReverse(),
+				NewPart(" of ").SetNumbering("$").SetLevel(1),
+				NewPart("!"),
+			},
+		},
+		{
+			name:  "escaped marker",
+			value: `\$5 and $`,
+			want:  AttrValues{NewPart("$5 and ").SetNumbering("$")},
+		},
+		{
+			name:  "wrapped content placeholder",
+			value: "/$#/$",
+			want:  AttrValues{NewContent("/"), NewPart("/").SetNumbering("$")},
+		},
+		{
+			name:  "only escaped markers",
+			value: `\$\$`,
+			want:  AttrValues{NewPart("$$")},
+		},
+		{
+			name:  "invalid marker is literal",
+			value: "$@",
+			want:  AttrValues{NewPart("$@")},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := NewLexer(ModeHTML).FindNumberedParts(tt.value)
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
 func TestLexer_FindClassOrIDToken(t *testing.T) {
 	t.Parallel()
 
@@ -312,7 +439,7 @@ func TestLexer_FindClassOrIDToken(t *testing.T) {
 			sut:        NewLexer(ModeHTML),
 			args:       args{runes: []rune("#")},
 			wantToken:  nil,
-			wantLength: 0,
+			wantLength: 1,
 			wantErr:    assert.Error,
 		},
 		{
@@ -328,7 +455,7 @@ func TestLexer_FindClassOrIDToken(t *testing.T) {
 			sut:        NewLexer(ModeHTML),
 			args:       args{runes: []rune(".")},
 			wantToken:  nil,
-			wantLength: 0,
+			wantLength: 1,
 			wantErr:    assert.Error,
 		},
 		{
@@ -379,6 +506,22 @@ func TestLexer_FindClassOrIDToken(t *testing.T) {
 			wantLength: 5,
 			wantErr:    assert.Error,
 		},
+		{
+			name:       "outer level",
+			sut:        NewLexer(ModeHTML),
+			args:       args{runes: []rune(".item$$@-3~12>p")},
+			wantToken:  NewClass("item").SetNumbering("$$").SetStart(3).SetReverse().SetLevel(12),
+			wantLength: 13,
+			wantErr:    assert.NoError,
+		},
+		{
+			name:       "missing level",
+			sut:        NewLexer(ModeHTML),
+			args:       args{runes: []rune(".item$~")},
+			wantToken:  nil,
+			wantLength: 7,
+			wantErr:    assert.Error,
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -491,6 +634,66 @@ func TestLexer_FindAttribute(t *testing.T) {
 			wantLength:       17,
 			wantErr:          assert.NoError,
 		},
+		{
+			name:             "complete with single quoted Value",
+			sut:              NewLexer(ModeHTML),
+			args:             args{runes: []rune(`foobar='bar! "foo"'`)},
+			wantName:         "foobar",
+			wantValue:        `bar! "foo"`,
+			wantHasEqualSign: true,
+			wantLength:       19,
+			wantErr:          assert.NoError,
+		},
+		{
+			name:             "jsx expression",
+			sut:              NewLexer(ModeJSX),
+			args:             args{runes: []rune(`onClick={() => save({id: 1})}]`)},
+			wantName:         "onClick",
+			wantValue:        `() => save({id: 1})`,
+			wantHasEqualSign: true,
+			wantLength:       29,
+			wantErr:          assert.NoError,
+		},
+		{
+			name:             "unterminated jsx expression",
+			sut:              NewLexer(ModeJSX),
+			args:             args{runes: []rune(`onClick={save(]`)},
+			wantName:         "onClick",
+			wantValue:        "",
+			wantHasEqualSign: true,
+			wantLength:       15,
+			wantErr:          assert.Error,
+		},
+		{
+			name:             "braces outside of jsx",
+			sut:              NewLexer(ModeHTML),
+			args:             args{runes: []rune(`title={x}]`)},
+			wantName:         "title",
+			wantValue:        "{x}",
+			wantHasEqualSign: true,
+			wantLength:       9,
+			wantErr:          assert.NoError,
+		},
+		{
+			name:             "boolean marker",
+			sut:              NewLexer(ModeHTML),
+			args:             args{runes: []rune("foobar. ")},
+			wantName:         "foobar",
+			wantValue:        "",
+			wantHasEqualSign: false,
+			wantLength:       7,
+			wantErr:          assert.NoError,
+		},
+		{
+			name:             "dot inside name",
+			sut:              NewLexer(ModeHTML),
+			args:             args{runes: []rune("foo.bar")},
+			wantName:         "",
+			wantValue:        "",
+			wantHasEqualSign: false,
+			wantLength:       3,
+			wantErr:          assert.Error,
+		},
 		{
 			name:             "! instead of =",
 			sut:              NewLexer(ModeHTML),
@@ -501,6 +704,26 @@ func TestLexer_FindAttribute(t *testing.T) {
 			wantLength:       6,
 			wantErr:          assert.Error,
 		},
+		{
+			name:             "unterminated double quoted value",
+			sut:              NewLexer(ModeHTML),
+			args:             args{runes: []rune(`title="abc`)},
+			wantName:         "",
+			wantValue:        "",
+			wantHasEqualSign: false,
+			wantLength:       10,
+			wantErr:          assert.Error,
+		},
+		{
+			name:             "unterminated single quoted value",
+			sut:              NewLexer(ModeHTML),
+			args:             args{runes: []rune(`x='`)},
+			wantName:         "",
+			wantValue:        "",
+			wantHasEqualSign: false,
+			wantLength:       3,
+			wantErr:          assert.Error,
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -540,7 +763,7 @@ func TestLexer_NextAttributeToken(t *testing.T) {
 				runes: []rune(`[`),
 			},
 			wantTokens: nil,
-			wantLength: 0,
+			wantLength: 1,
 			wantErr:    assert.Error,
 		},
 		{
@@ -550,7 +773,7 @@ func TestLexer_NextAttributeToken(t *testing.T) {
 				runes: []rune(`[foobar`),
 			},
 			wantTokens: nil,
-			wantLength: 0,
+			wantLength: 7,
 			wantErr:    assert.Error,
 		},
 		{
@@ -613,6 +836,19 @@ func TestLexer_NextAttributeToken(t *testing.T) {
 			wantLength: 20,
 			wantErr:    assert.NoError,
 		},
+		{
+			name: "boolean marker and single quotes",
+			sut:  NewLexer(ModeHTML),
+			args: args{
+				runes: []rune(`[foo. title='Hello world!']`),
+			},
+			wantTokens: []*Attr{
+				NewAttr("foo", "").HasNoEqualSign().SetBoolean(),
+				NewAttr("title", "Hello world!"),
+			},
+			wantLength: 27,
+			wantErr:    assert.NoError,
+		},
 		{
 			name: "complex",
 			sut:  NewLexer(ModeHTML),
@@ -908,12 +1144,12 @@ func TestLexer_FindRepeat(t *testing.T) {
 			wantErr:    assert.NoError,
 		},
 		{
-			name:       "single *",
+			name:       "single * is an implicit repeat",
 			sut:        NewLexer(ModeHTML),
 			args:       args{runes: []rune("*")},
-			wantLength: 0,
+			wantLength: 1,
 			wantRepeat: 0,
-			wantErr:    assert.Error,
+			wantErr:    assert.NoError,
 		},
 		{
 			name:       "valid",
@@ -974,7 +1210,7 @@ func TestLexer_NextTextToken(t *testing.T) {
 			sut:        NewLexer(ModeHTML),
 			args:       args{runes: []rune("{foo=bar")},
 			wantToken:  nil,
-			wantLength: 0,
+			wantLength: 8,
 			wantErr:    assert.Error,
 		},
 		{
@@ -1025,6 +1261,22 @@ func TestLexer_NextTextToken(t *testing.T) {
 			wantLength: 25,
 			wantErr:    assert.NoError,
 		},
+		{
+			name:       "valid non-empty, nested braces",
+			sut:        NewLexer(ModeHTML),
+			args:       args{runes: []rune("{{{ .Name }}}+p")},
+			wantToken:  NewText("{{ .Name }}"),
+			wantLength: 13,
+			wantErr:    assert.NoError,
+		},
+		{
+			name:       "invalid non-empty, unbalanced braces",
+			sut:        NewLexer(ModeHTML),
+			args:       args{runes: []rune("{{ .Name }")},
+			wantToken:  nil,
+			wantLength: 10,
+			wantErr:    assert.Error,
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -1072,29 +1324,101 @@ func TestLexer_NextTagToken(t *testing.T) {
 			wantErr:    assert.NoError,
 		},
 		{
-			name:       "dash not supported",
+			name:       "custom element",
 			sut:        NewLexer(ModeHTML),
 			args:       args{runes: []rune("div-p")},
-			wantToken:  NewTagToken("div", 1),
-			wantLength: 3,
+			wantToken:  NewTagToken("div-p", 1),
+			wantLength: 5,
+			wantErr:    assert.NoError,
+		},
+		{
+			name:       "custom element with non-ascii letters",
+			sut:        NewLexer(ModeHTML),
+			args:       args{runes: []rune("emotion-😍+p")},
+			wantToken:  NewTagToken("emotion-😍", 1),
+			wantLength: 9,
+			wantErr:    assert.NoError,
+		},
+		{
+			name:       "custom element followed by a class",
+			sut:        NewLexer(ModeHTML),
+			args:       args{runes: []rune("my-card.active")},
+			wantToken:  NewTagToken("my-card", 1).AddClass(NewClass("active")),
+			wantLength: 14,
 			wantErr:    assert.NoError,
 		},
 		{
-			name:       "underscore not supported",
+			name:       "custom element must start with a lowercase letter",
+			sut:        NewLexer(ModeHTML),
+			args:       args{runes: []rune("My-card")},
+			wantToken:  nil,
+			wantLength: 0,
+			wantErr:    assert.Error,
+		},
+		{
+			name:       "custom element can not contain uppercase letters",
+			sut:        NewLexer(ModeHTML),
+			args:       args{runes: []rune("my-Card")},
+			wantToken:  nil,
+			wantLength: 3,
+			wantErr:    assert.Error,
+		},
+		{
+			name:       "underscore not supported in html",
 			sut:        NewLexer(ModeHTML),
 			args:       args{runes: []rune("div_p")},
-			wantToken:  NewTagToken("div", 1),
+			wantToken:  nil,
 			wantLength: 3,
-			wantErr:    assert.NoError,
+			wantErr:    assert.Error,
 		},
 		{
-			name:       "strange character",
+			name:       "strange character not supported in html",
 			sut:        NewLexer(ModeHTML),
 			args:       args{runes: []rune("divőp")},
-			wantToken:  NewTagToken("div", 1),
+			wantToken:  nil,
 			wantLength: 3,
+			wantErr:    assert.Error,
+		},
+		{
+			name:       "leading digit not supported in html",
+			sut:        NewLexer(ModeHTML),
+			args:       args{runes: []rune("1div")},
+			wantToken:  nil,
+			wantLength: 0,
+			wantErr:    assert.Error,
+		},
+		{
+			name:       "snippet with dash",
+			sut:        NewLexer(ModeHTML),
+			args:       args{runes: []rune("input:datetime-local")},
+			wantToken:  NewTagToken("input:datetime-local", 1),
+			wantLength: 20,
+			wantErr:    assert.NoError,
+		},
+		{
+			name:       "xml name",
+			sut:        NewLexer(ModeXML),
+			args:       args{runes: []rune("xs:complex-type_2>x")},
+			wantToken:  NewTagToken("xs:complex-type_2", 1),
+			wantLength: 17,
+			wantErr:    assert.NoError,
+		},
+		{
+			name:       "xml name with non-ascii letters",
+			sut:        NewLexer(ModeXML),
+			args:       args{runes: []rune("_árvíztűrő·tükörfúrógép")},
+			wantToken:  NewTagToken("_árvíztűrő·tükörfúrógép", 1),
+			wantLength: 23,
 			wantErr:    assert.NoError,
 		},
+		{
+			name:       "xml name can not start with a dash",
+			sut:        NewLexer(ModeXML),
+			args:       args{runes: []rune("-item")},
+			wantToken:  nil,
+			wantLength: 0,
+			wantErr:    assert.Error,
+		},
 		{
 			name:       "empty",
 			sut:        NewLexer(ModeHTML),
@@ -1106,11 +1430,64 @@ func TestLexer_NextTagToken(t *testing.T) {
 		{
 			name:       "invalid character",
 			sut:        NewLexer(ModeHTML),
-			args:       args{runes: []rune("!p")},
+			args:       args{runes: []rune("%p")},
 			wantToken:  nil,
 			wantLength: 0,
 			wantErr:    assert.Error,
 		},
+		{
+			name:       "document snippet",
+			sut:        NewLexer(ModeHTML),
+			args:       args{runes: []rune("!>p")},
+			wantToken:  NewTagToken("!", 1),
+			wantLength: 1,
+			wantErr:    assert.NoError,
+		},
+		{
+			name:       "doctype snippet",
+			sut:        NewLexer(ModeHTML),
+			args:       args{runes: []rune("!!!")},
+			wantToken:  NewTagToken("!!!", 1),
+			wantLength: 3,
+			wantErr:    assert.NoError,
+		},
+		{
+			name:       "document snippet not supported in xml",
+			sut:        NewLexer(ModeXML),
+			args:       args{runes: []rune("!")},
+			wantToken:  nil,
+			wantLength: 0,
+			wantErr:    assert.Error,
+		},
+		{
+			name:       "too many exclamation marks",
+			sut:        NewLexer(ModeHTML),
+			args:       args{runes: []rune("!!")},
+			wantToken:  nil,
+			wantLength: 0,
+			wantErr:    assert.Error,
+		},
+		{
+			name: "implicit tag name",
+			sut:  NewLexer(ModeHTML),
+			args: args{runes: []rune(".item*2")},
+			wantToken: NewTagToken("", 2).
+				AddClass(NewClass("item")),
+			wantLength: 7,
+			wantErr:    assert.NoError,
+		},
+		{
+			name: "multiplier after text",
+			sut:  NewLexer(ModeHTML),
+			args: args{runes: []rune("li{Item}*3>a")},
+			wantToken: &TagToken{
+				Name:   "li",
+				Repeat: 3,
+				Text:   NewText("Item"),
+			},
+			wantLength: 10,
+			wantErr:    assert.NoError,
+		},
 		{
 			name:       "valid multiplier",
 			sut:        NewLexer(ModeHTML),
@@ -1128,27 +1505,51 @@ func TestLexer_NextTagToken(t *testing.T) {
 			wantErr:    assert.NoError,
 		},
 		{
-			name:       "invalid * if not continued",
+			name:       "implicit repeat",
 			sut:        NewLexer(ModeHTML),
 			args:       args{runes: []rune("div*")},
-			wantToken:  nil,
-			wantLength: 3,
-			wantErr:    assert.Error,
+			wantToken:  NewTagToken("div", 1).SetRepeat(0),
+			wantLength: 4,
+			wantErr:    assert.NoError,
 		},
 		{
-			name:       "invalid * without valid number",
+			name:       "implicit repeat, continued",
 			sut:        NewLexer(ModeHTML),
 			args:       args{runes: []rune("div*p")},
-			wantToken:  nil,
+			wantToken:  NewTagToken("div", 1).SetRepeat(0),
+			wantLength: 4,
+			wantErr:    assert.NoError,
+		},
+		{
+			name:       "subtree snippet",
+			sut:        NewLexer(ModeHTML),
+			args:       args{runes: []rune("ul+")},
+			wantToken:  NewTagToken("ul+", 1),
 			wantLength: 3,
-			wantErr:    assert.Error,
+			wantErr:    assert.NoError,
+		},
+		{
+			name:       "subtree snippet with class and repeat, continued",
+			sut:        NewLexer(ModeHTML),
+			args:       args{runes: []rune("table.data+*2>tr")},
+			wantToken:  NewTagToken("table+", 2).AddClass(NewClass("data")),
+			wantLength: 13,
+			wantErr:    assert.NoError,
+		},
+		{
+			name:       "sibling is not a subtree snippet",
+			sut:        NewLexer(ModeHTML),
+			args:       args{runes: []rune("ul+p")},
+			wantToken:  NewTagToken("ul", 1),
+			wantLength: 2,
+			wantErr:    assert.NoError,
 		},
 		{
 			name:       "invalid empty class",
 			sut:        NewLexer(ModeHTML),
 			args:       args{runes: []rune("div.")},
 			wantToken:  nil,
-			wantLength: 3,
+			wantLength: 4,
 			wantErr:    assert.Error,
 		},
 		{
@@ -1659,3 +2060,113 @@ func TestLexer_Tokenize(t *testing.T) {
 		})
 	}
 }
+
+func TestLexer_Tokenize_SyntaxError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		s         string
+		wantErr   *SyntaxError
+		wantCaret string
+	}{
+		{
+			name: "missing class name",
+			s:    "ul>li.",
+			wantErr: &SyntaxError{
+				Offset: 6, Line: 1, Column: 7,
+				Expected: "class name", Found: endOfInput, Err: ErrInputTooShort,
+			},
+			wantCaret: "ul>li.\n      ^",
+		},
+		{
+			name: "error in nested group",
+			s:    "div>(ul>(li.)+p)",
+			wantErr: &SyntaxError{
+				Offset: 12, Line: 1, Column: 13,
+				Expected: "class name", Found: "')'", Err: ErrInputTooShort,
+			},
+			wantCaret: "div>(ul>(li.)+p)\n            ^",
+		},
+		{
+			name: "unclosed group",
+			s:    "(ul>li",
+			wantErr: &SyntaxError{
+				Offset: 6, Line: 1, Column: 7,
+				Expected: "')'", Found: endOfInput, Err: ErrDirectiveClosingMissing,
+			},
+			wantCaret: "(ul>li\n      ^",
+		},
+		{
+			name: "unexpected group closing",
+			s:    "p)+a",
+			wantErr: &SyntaxError{
+				Offset: 1, Line: 1, Column: 2,
+				Expected: "'+', '>' or '^'", Found: "')'", Err: ErrUnexpectedGroupClosing,
+			},
+			wantCaret: "p)+a\n ^",
+		},
+		{
+			name: "invalid directive",
+			s:    "p>a!",
+			wantErr: &SyntaxError{
+				Offset: 3, Line: 1, Column: 4,
+				Expected: "'+', '>', '^' or ')'", Found: "'!'", Err: ErrInvalidCharacter,
+			},
+			wantCaret: "p>a!\n   ^",
+		},
+		{
+			name: "unclosed text",
+			s:    "p{foo",
+			wantErr: &SyntaxError{
+				Offset: 5, Line: 1, Column: 6,
+				Expected: "'}'", Found: endOfInput, Err: ErrDirectiveClosingMissing,
+			},
+			wantCaret: "p{foo\n     ^",
+		},
+		{
+			name: "unterminated double quoted attribute value",
+			s:    `a[title="abc`,
+			wantErr: &SyntaxError{
+				Offset: 12, Line: 1, Column: 13,
+				Expected: `'"'`, Found: endOfInput, Err: ErrDirectiveClosingMissing,
+			},
+			wantCaret: "a[title=\"abc\n            ^",
+		},
+		{
+			name: "unterminated single quoted attribute value",
+			s:    "a[x='",
+			wantErr: &SyntaxError{
+				Offset: 5, Line: 1, Column: 6,
+				Expected: `'\''`, Found: endOfInput, Err: ErrDirectiveClosingMissing,
+			},
+			wantCaret: "a[x='\n     ^",
+		},
+		{
+			name: "second line",
+			s:    "p{a\nb}+\t!",
+			wantErr: &SyntaxError{
+				Offset: 7, Line: 2, Column: 4,
+				Expected: "tag name", Found: "'\\t'", Err: ErrInputTooShort,
+			},
+			wantCaret: "b}+\t!\n   ^",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, gotPos, err := NewLexer(ModeHTML).Tokenize([]rune(tt.s), false)
+
+			var gotErr *SyntaxError
+
+			require.ErrorAs(t, err, &gotErr)
+			assert.Equal(t, tt.wantErr, gotErr)
+			assert.Equal(t, tt.wantErr.Offset, gotPos)
+			assert.ErrorIs(t, err, tt.wantErr.Err)
+			assert.Equal(t, tt.wantCaret, gotErr.Caret(tt.s))
+		})
+	}
+}
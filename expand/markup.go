@@ -0,0 +1,334 @@
+package expand
+
+import (
+	"fmt"
+	"strings"
+)
+
+// nolint: gochecknoglobals
+var shortHTMLTagNames = map[string]struct{}{
+	"br":      {},
+	"hr":      {},
+	"img":     {},
+	"input":   {},
+	"link":    {},
+	"meta":    {},
+	"area":    {},
+	"base":    {},
+	"col":     {},
+	"command": {},
+	"embed":   {},
+	"keygen":  {},
+	"param":   {},
+	"source":  {},
+	"video":   {},
+	"audio":   {},
+	"track":   {},
+	"wbr":     {},
+}
+
+// jsxAttributeNames maps the attributes which are called differently in JSX, the camelCase names of the React DOM
+// props. Boolean attributes are included as they are written as props, e.g. readOnly={true}.
+//
+// nolint: gochecknoglobals
+var jsxAttributeNames = map[string]string{
+	"class":           "className",
+	"for":             "htmlFor",
+	"accept-charset":  "acceptCharset",
+	"accesskey":       "accessKey",
+	"allowfullscreen": "allowFullScreen",
+	"autocomplete":    "autoComplete",
+	"autofocus":       "autoFocus",
+	"autoplay":        "autoPlay",
+	"charset":         "charSet",
+	"colspan":         "colSpan",
+	"contenteditable": "contentEditable",
+	"crossorigin":     "crossOrigin",
+	"datetime":        "dateTime",
+	"enctype":         "encType",
+	"enterkeyhint":    "enterKeyHint",
+	"formaction":      "formAction",
+	"formnovalidate":  "formNoValidate",
+	"frameborder":     "frameBorder",
+	"http-equiv":      "httpEquiv",
+	"inputmode":       "inputMode",
+	"ismap":           "isMap",
+	"itemscope":       "itemScope",
+	"maxlength":       "maxLength",
+	"minlength":       "minLength",
+	"nomodule":        "noModule",
+	"novalidate":      "noValidate",
+	"playsinline":     "playsInline",
+	"readonly":        "readOnly",
+	"referrerpolicy":  "referrerPolicy",
+	"rowspan":         "rowSpan",
+	"spellcheck":      "spellCheck",
+	"srcset":          "srcSet",
+	"tabindex":        "tabIndex",
+	"usemap":          "useMap",
+}
+
+// MarkupRenderer writes angle-bracket markup: HTML, XML or JSX depending on the mode.
+type MarkupRenderer struct{}
+
+func (r MarkupRenderer) Render(builder *strings.Builder, elemList ElemList, counter *Counter, opts Options) {
+	for _, e := range elemList {
+		r.elem(builder, e, counter, opts)
+	}
+}
+
+func (r MarkupRenderer) elem(builder *strings.Builder, e *Elem, counter *Counter, opts Options) {
+	// void and empty XML elements stay self-closed with tab stops too, only their attributes get tab stops
+	shortTag := e.selfCloses(opts.Mode)
+	emptyTag := e.isEmptyTag()
+
+	currentIndentation := ""
+	if opts.Indentation != "" {
+		currentIndentation = strings.Repeat(opts.Indentation, opts.Depth)
+	}
+
+	if e.Raw != "" {
+		r.raw(builder, e, opts, currentIndentation)
+
+		return
+	}
+
+	if e.Name == "" {
+		r.text(builder, e, opts, currentIndentation, "")
+
+		return
+	}
+
+	if opts.Multiline && e.hasInlineContent(opts) {
+		r.inline(builder, e, counter, opts, currentIndentation)
+		r.comment(builder, e, opts, currentIndentation)
+
+		return
+	}
+
+	r.openingTag(builder, e, counter, opts, currentIndentation, shortTag)
+
+	if opts.Multiline && (!emptyTag || shortTag) {
+		builder.WriteString("\n")
+	}
+
+	if !shortTag {
+		r.text(builder, e, opts, currentIndentation, opts.Indentation)
+
+		r.tabStop(builder, e, counter, opts)
+
+		r.children(builder, e, counter, opts)
+
+		r.closingTag(builder, e, currentIndentation, opts.Multiline, emptyTag)
+	}
+
+	r.comment(builder, e, opts, currentIndentation)
+}
+
+// inline writes e on a line of its own, with its text and children on the same line. It's used in multiline output
+// when all children of e are inline, or e only has text.
+func (r MarkupRenderer) inline(builder *strings.Builder, e *Elem, counter *Counter, opts Options, currentIndentation string) {
+	inline := opts
+	inline.Multiline = false
+
+	builder.WriteString(currentIndentation)
+
+	r.openingTag(builder, e, counter, inline, "", false)
+	r.text(builder, e, inline, "", "")
+	r.tabStop(builder, e, counter, inline)
+	r.children(builder, e, counter, inline)
+	r.closingTag(builder, e, "", false, false)
+
+	builder.WriteString("\n")
+}
+
+func (r MarkupRenderer) text(builder *strings.Builder, e *Elem, opts Options, currentIndentation, indentationExtra string) {
+	if e.Text.IsEmpty() || !opts.Multiline {
+		builder.WriteString(e.GetText(opts))
+
+		return
+	}
+
+	indentation := currentIndentation + indentationExtra
+
+	for _, line := range strings.Split(e.GetText(opts), "\n") {
+		if line != "" {
+			builder.WriteString(indentation)
+			builder.WriteString(line)
+		}
+
+		builder.WriteString("\n")
+	}
+}
+
+// raw writes raw markup on its own line.
+func (r MarkupRenderer) raw(builder *strings.Builder, e *Elem, opts Options, currentIndentation string) {
+	if !opts.Multiline {
+		builder.WriteString(e.Raw)
+
+		return
+	}
+
+	builder.WriteString(currentIndentation)
+	builder.WriteString(e.Raw)
+	builder.WriteString("\n")
+}
+
+func (r MarkupRenderer) openingTag(builder *strings.Builder, e *Elem, counter *Counter, opts Options, currentIndentation string, shortTag bool) {
+	if opts.Multiline {
+		builder.WriteString(currentIndentation)
+	}
+
+	builder.WriteString("<")
+	builder.WriteString(e.Name)
+
+	quote := opts.quote()
+
+	id := e.GetID()
+
+	if id != "" {
+		builder.WriteString(" id=")
+		builder.WriteString(quote)
+		builder.WriteString(id)
+		builder.WriteString(quote)
+	}
+
+	if len(e.Attributes) > 0 {
+		builder.WriteString(" ")
+		builder.WriteString(r.attrs(e, counter, opts))
+	}
+
+	if len(e.Classes) > 0 {
+		builder.WriteString(" " + opts.attributeName("class") + "=")
+		builder.WriteString(quote)
+		builder.WriteString(e.GetClass())
+		builder.WriteString(quote)
+	}
+
+	if shortTag {
+		builder.WriteString(opts.selfClosingEnd())
+
+		return
+	}
+
+	builder.WriteString(">")
+}
+
+func (r MarkupRenderer) attrs(e *Elem, counter *Counter, opts Options) string {
+	quote := opts.quote()
+
+	attrs := []string{}
+	for _, attr := range e.Attributes {
+		name := opts.attributeName(attr.Name)
+
+		if attr.IsBoolean() {
+			attrs = append(attrs, opts.booleanAttribute(name))
+
+			continue
+		}
+
+		if attr.Expression {
+			attrs = append(attrs, fmt.Sprintf(`%s={%s}`, name, attr.GetExpression(counter, opts)))
+
+			continue
+		}
+
+		attrs = append(attrs, fmt.Sprintf(`%s=%s%s%s`, name, quote, e.attrValue(attr, counter, opts), quote))
+	}
+
+	return strings.Join(attrs, " ")
+}
+
+func (r MarkupRenderer) tabStop(builder *strings.Builder, e *Elem, counter *Counter, opts Options) {
+	if len(e.Children) != 0 {
+		return
+	}
+
+	builder.WriteString(opts.tabStop(counter.Get(), ""))
+}
+
+func (r MarkupRenderer) children(builder *strings.Builder, e *Elem, counter *Counter, opts Options) {
+	if len(e.Children) == 0 {
+		return
+	}
+
+	opts.Depth++
+
+	for _, child := range e.Children {
+		r.elem(builder, child, counter, opts)
+	}
+}
+
+func (r MarkupRenderer) closingTag(builder *strings.Builder, e *Elem, currentIndentation string, multiline, emptyTag bool) {
+	if multiline && !emptyTag {
+		builder.WriteString(currentIndentation)
+	}
+
+	builder.WriteString("</")
+	builder.WriteString(e.Name)
+	builder.WriteString(">")
+
+	if multiline {
+		builder.WriteString("\n")
+	}
+}
+
+// comment writes the comment of the comment filter after elements with an id or classes.
+func (r MarkupRenderer) comment(builder *strings.Builder, e *Elem, opts Options, currentIndentation string) {
+	comment := e.commentText(opts)
+	if comment == "" {
+		return
+	}
+
+	if opts.Multiline {
+		builder.WriteString(currentIndentation)
+	}
+
+	if opts.Mode == ModeJSX {
+		builder.WriteString("{/* " + comment + " */}")
+	} else {
+		builder.WriteString("<!-- " + comment + " -->")
+	}
+
+	if opts.Multiline {
+		builder.WriteString("\n")
+	}
+}
+
+// attributeName returns the name attributes are rendered with in the mode, e.g. className in JSX.
+func (o Options) attributeName(name string) string {
+	if jsxName, ok := jsxAttributeNames[strings.ToLower(name)]; ok && o.Mode == ModeJSX {
+		return jsxName
+	}
+
+	return name
+}
+
+func (o Options) booleanAttribute(name string) string {
+	if o.Mode == ModeJSX {
+		return name + "={true}"
+	}
+
+	if o.BooleanAttributes == BooleanAttributeExpanded {
+		return name + "=" + o.quote() + name + o.quote()
+	}
+
+	return name
+}
+
+func (o Options) selfClosingEnd() string {
+	switch o.SelfClosing {
+	case SelfClosingXHTML:
+		return " />"
+	case SelfClosingXML:
+		return "/>"
+	case SelfClosingHTML:
+		return ">"
+	}
+
+	if o.Mode.closesEmpty() {
+		return " />"
+	}
+
+	return ">"
+}
@@ -0,0 +1,262 @@
+package expand
+
+import (
+	"fmt"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrUnknownMode             = errors.New("unknown mode")
+	ErrUnknownTabStopStyle     = errors.New("unknown tab stop style")
+	ErrUnknownQuoteStyle       = errors.New("unknown quote style")
+	ErrInvalidSelfClosingStyle = errors.New("invalid self-closing style")
+	ErrInvalidBooleanAttrStyle = errors.New("invalid boolean attribute style")
+	ErrNegativeDepth           = errors.New("depth must not be negative")
+	ErrUnknownSyntax           = errors.New("unknown syntax")
+)
+
+type Mode string
+
+const (
+	ModeHTML Mode = "html"
+	ModeXML  Mode = "xml"
+	ModeHTMX Mode = "htmx"
+	// ModeJSX renders React JSX / TSX: HTML elements with className and htmlFor, every empty element self-closed.
+	ModeJSX Mode = "jsx"
+)
+
+func (m Mode) isHTML() bool {
+	return m == ModeHTML || m == ModeHTMX || m == ModeJSX
+}
+
+func (m Mode) isKnown() bool {
+	return m == ModeHTML || m == ModeXML || m == ModeHTMX || m == ModeJSX
+}
+
+// closesEmpty tells if all empty elements are self-closed, not only the void elements of HTML.
+func (m Mode) closesEmpty() bool {
+	return m == ModeXML || m == ModeJSX
+}
+
+// TabStopStyle decides how tab stops are rendered.
+type TabStopStyle string
+
+const (
+	// TabStopWrapped renders tab stops as <wrapper>STOP<n><wrapper>, disabled if the wrapper is empty.
+	TabStopWrapped TabStopStyle = "wrapped"
+	// TabStopSnippet renders tab stops in TextMate / LSP snippet syntax: ${1}, ${2:placeholder}.
+	TabStopSnippet TabStopStyle = "snippet"
+)
+
+type QuoteStyle string
+
+const (
+	QuoteDouble QuoteStyle = "double"
+	QuoteSingle QuoteStyle = "single"
+)
+
+// SelfClosingStyle decides how empty elements are closed, if they are allowed to be.
+type SelfClosingStyle string
+
+const (
+	SelfClosingHTML  SelfClosingStyle = "html"  // <br>
+	SelfClosingXHTML SelfClosingStyle = "xhtml" // <br />
+	SelfClosingXML   SelfClosingStyle = "xml"   // <br/>
+)
+
+// BooleanAttributeStyle decides how boolean attributes without a value, e.g. input[disabled], are rendered.
+type BooleanAttributeStyle string
+
+const (
+	BooleanAttributeMinimized BooleanAttributeStyle = "minimized" // disabled
+	BooleanAttributeExpanded  BooleanAttributeStyle = "expanded"  // disabled="disabled"
+)
+
+// Syntax is the template language the elements are written in, see Renderer.
+type Syntax string
+
+const (
+	SyntaxMarkup Syntax = "markup" // HTML, XML or JSX, decided by the mode
+	SyntaxPug    Syntax = "pug"
+	SyntaxHAML   Syntax = "haml"
+	SyntaxSlim   Syntax = "slim"
+)
+
+const (
+	DefaultIndentation = "    "
+)
+
+// Options configures a single expansion. Empty styles and modes fall back to their defaults, but the zero values of
+// Multiline and Indentation mean single-line output: start from DefaultOptions() for multiline output.
+type Options struct {
+	Mode           Mode
+	Indentation    string
+	Depth          int
+	Multiline      bool
+	TabStop        TabStopStyle
+	TabStopWrapper string
+	Quote          QuoteStyle
+	SelfClosing    SelfClosingStyle
+	// BooleanAttributes defaults to minimized for HTML and to expanded for XML and XHTML.
+	BooleanAttributes BooleanAttributeStyle
+	// Syntax defaults to markup. Indentation based syntaxes are always multiline, they fall back to the default
+	// indentation if Indentation is empty.
+	Syntax Syntax
+	// LoremSeed makes lorem ipsum output reproducible, 0 means random.
+	LoremSeed int64
+	// Raw writes attribute values and text without escaping, e.g. to inject template expressions like {{ .Name }}.
+	Raw bool
+	// Recover expands the longest valid prefix of broken abbreviations instead of failing.
+	Recover bool
+	// Snippets are user-defined snippets, they take precedence over the built-in ones.
+	Snippets Snippets
+	// InlineElements are kept on the line of their parent in multiline output if all its children are inline,
+	// defaults to the inline-level elements of the mode if nil.
+	InlineElements []string
+
+	faker *gofakeit.Faker
+	// wrap is the content inserted by Wrap, nil when expanding.
+	wrap *wrapContent
+	// filters are the ones given at the end of the abbreviation.
+	filters []Filter
+}
+
+func DefaultOptions() Options {
+	return Options{
+		Mode:        ModeHTML,
+		Indentation: DefaultIndentation,
+		Depth:       0,
+		Multiline:   true,
+		TabStop:     TabStopWrapped,
+		Quote:       QuoteDouble,
+	}
+}
+
+func (o Options) Validate() error {
+	if o.Mode != "" && !o.Mode.isKnown() {
+		return errors.Wrapf(ErrUnknownMode, "mode: %q", o.Mode)
+	}
+
+	if o.Depth < 0 {
+		return errors.Wrapf(ErrNegativeDepth, "depth: %d", o.Depth)
+	}
+
+	switch o.TabStop {
+	case "", TabStopWrapped, TabStopSnippet:
+	default:
+		return errors.Wrapf(ErrUnknownTabStopStyle, "tab stop style: %q", o.TabStop)
+	}
+
+	switch o.Quote {
+	case "", QuoteDouble, QuoteSingle:
+	default:
+		return errors.Wrapf(ErrUnknownQuoteStyle, "quote style: %q", o.Quote)
+	}
+
+	switch o.SelfClosing {
+	case "", SelfClosingHTML, SelfClosingXHTML, SelfClosingXML:
+	default:
+		return errors.Wrapf(ErrInvalidSelfClosingStyle, "self-closing style: %q", o.SelfClosing)
+	}
+
+	// XML and JSX have no void elements, so empty elements must be closed explicitly
+	if o.Mode.closesEmpty() && o.SelfClosing == SelfClosingHTML {
+		return errors.Wrapf(ErrInvalidSelfClosingStyle, "self-closing style %q in mode %q", o.SelfClosing, o.Mode)
+	}
+
+	switch o.BooleanAttributes {
+	case "", BooleanAttributeMinimized, BooleanAttributeExpanded:
+	default:
+		return errors.Wrapf(ErrInvalidBooleanAttrStyle, "boolean attribute style: %q", o.BooleanAttributes)
+	}
+
+	// XML attributes must always have a value
+	if o.Mode == ModeXML && o.BooleanAttributes == BooleanAttributeMinimized {
+		return errors.Wrapf(ErrInvalidBooleanAttrStyle, "boolean attribute style %q in mode %q", o.BooleanAttributes, o.Mode)
+	}
+
+	if _, ok := renderers[o.Syntax]; o.Syntax != "" && !ok {
+		return errors.Wrapf(ErrUnknownSyntax, "syntax: %q", o.Syntax)
+	}
+
+	// className, htmlFor and expressions only make sense in JSX markup
+	if o.Mode == ModeJSX && o.Syntax != "" && o.Syntax != SyntaxMarkup {
+		return errors.Wrapf(ErrUnknownSyntax, "syntax %q in mode %q", o.Syntax, o.Mode)
+	}
+
+	if err := o.Snippets.Validate(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (o Options) withDefaults() Options {
+	if o.Mode == "" {
+		o.Mode = ModeHTML
+	}
+
+	if o.TabStop == "" {
+		o.TabStop = TabStopWrapped
+	}
+
+	if o.Syntax == "" {
+		o.Syntax = SyntaxMarkup
+	}
+
+	if o.Quote == "" {
+		o.Quote = QuoteDouble
+	}
+
+	if o.SelfClosing == "" {
+		o.SelfClosing = SelfClosingHTML
+
+		if o.Mode.closesEmpty() {
+			o.SelfClosing = SelfClosingXHTML
+		}
+	}
+
+	if o.BooleanAttributes == "" {
+		o.BooleanAttributes = BooleanAttributeMinimized
+
+		if o.SelfClosing != SelfClosingHTML {
+			o.BooleanAttributes = BooleanAttributeExpanded
+		}
+	}
+
+	if o.faker == nil && o.LoremSeed != 0 {
+		o.faker = gofakeit.New(o.LoremSeed)
+	}
+
+	return o
+}
+
+func (o Options) tabStop(count int, placeholder string) string {
+	if o.TabStop == tabStopMarked {
+		return markTabStop(count, placeholder)
+	}
+
+	if o.TabStop == TabStopSnippet {
+		if placeholder == "" {
+			return fmt.Sprintf("${%d}", count)
+		}
+
+		return fmt.Sprintf("${%d:%s}", count, placeholder)
+	}
+
+	if o.TabStopWrapper == "" {
+		return placeholder
+	}
+
+	return fmt.Sprintf("%s%sSTOP%d%s", placeholder, o.TabStopWrapper, count, o.TabStopWrapper)
+}
+
+func (o Options) quote() string {
+	if o.Quote == QuoteSingle {
+		return "'"
+	}
+
+	return `"`
+}
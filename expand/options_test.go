@@ -0,0 +1,226 @@
+package expand
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptions_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		sut     Options
+		wantErr error
+	}{
+		{
+			name:    "zero value",
+			sut:     Options{},
+			wantErr: nil,
+		},
+		{
+			name:    "defaults",
+			sut:     DefaultOptions(),
+			wantErr: nil,
+		},
+		{
+			name:    "unknown mode",
+			sut:     Options{Mode: "jade"},
+			wantErr: ErrUnknownMode,
+		},
+		{
+			name:    "negative depth",
+			sut:     Options{Depth: -1},
+			wantErr: ErrNegativeDepth,
+		},
+		{
+			name:    "unknown tab stop style",
+			sut:     Options{TabStop: "vim"},
+			wantErr: ErrUnknownTabStopStyle,
+		},
+		{
+			name:    "unknown quote style",
+			sut:     Options{Quote: "backtick"},
+			wantErr: ErrUnknownQuoteStyle,
+		},
+		{
+			name:    "unknown self-closing style",
+			sut:     Options{SelfClosing: "sgml"},
+			wantErr: ErrInvalidSelfClosingStyle,
+		},
+		{
+			name:    "html self-closing style in xml mode",
+			sut:     Options{Mode: ModeXML, SelfClosing: SelfClosingHTML},
+			wantErr: ErrInvalidSelfClosingStyle,
+		},
+		{
+			name:    "unknown syntax",
+			sut:     Options{Syntax: "jade"},
+			wantErr: ErrUnknownSyntax,
+		},
+		{
+			name:    "pug in jsx mode",
+			sut:     Options{Mode: ModeJSX, Syntax: SyntaxPug},
+			wantErr: ErrUnknownSyntax,
+		},
+		{
+			name:    "html self-closing style in jsx mode",
+			sut:     Options{Mode: ModeJSX, SelfClosing: SelfClosingHTML},
+			wantErr: ErrInvalidSelfClosingStyle,
+		},
+		{
+			name:    "unknown boolean attribute style",
+			sut:     Options{BooleanAttributes: "empty"},
+			wantErr: ErrInvalidBooleanAttrStyle,
+		},
+		{
+			name:    "minimized boolean attributes in xml mode",
+			sut:     Options{Mode: ModeXML, BooleanAttributes: BooleanAttributeMinimized},
+			wantErr: ErrInvalidBooleanAttrStyle,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gotErr := tt.sut.Validate()
+
+			if tt.wantErr == nil {
+				require.NoError(t, gotErr)
+
+				return
+			}
+
+			assert.ErrorIs(t, gotErr, tt.wantErr)
+		})
+	}
+}
+
+func TestExpand_Options(t *testing.T) {
+	t.Parallel()
+
+	t.Run("unknown mode is rejected", func(t *testing.T) {
+		t.Parallel()
+
+		got, gotErr := Expand("div", Options{Mode: "jade"})
+		require.ErrorIs(t, gotErr, ErrUnknownMode)
+		assert.Contains(t, gotErr.Error(), ErrInvalidOptionsMsg)
+		assert.Empty(t, got)
+	})
+
+	t.Run("lorem seed is reproducible", func(t *testing.T) {
+		t.Parallel()
+
+		opts := Options{LoremSeed: 42}
+
+		got1, gotErr := Expand("p*3{lorem10}", opts)
+		require.NoError(t, gotErr)
+
+		got2, gotErr := Expand("p*3{lorem10}", opts)
+		require.NoError(t, gotErr)
+
+		assert.Equal(t, got1, got2)
+	})
+
+	tests := []struct {
+		name string
+		abbr string
+		opts Options
+		want string
+	}{
+		{
+			name: "zero value options",
+			abbr: "p.foo>br",
+			opts: Options{},
+			want: `<p class="foo"><br></p>`,
+		},
+		{
+			name: "single quotes",
+			abbr: "p#bar.foo[baz=qux]",
+			opts: Options{Quote: QuoteSingle},
+			want: `<p id='bar' baz='qux' class='foo'></p>`,
+		},
+		{
+			name: "xhtml self-closing style",
+			abbr: "p>br+img[src=a.png alt=]",
+			opts: Options{SelfClosing: SelfClosingXHTML},
+			want: `<p><br /><img src="a.png" alt="" /></p>`,
+		},
+		{
+			name: "xml self-closing style",
+			abbr: "item+item",
+			opts: Options{Mode: ModeXML, SelfClosing: SelfClosingXML},
+			want: `<item/><item/>`,
+		},
+		{
+			name: "htmx void elements",
+			abbr: "div>br",
+			opts: Options{Mode: ModeHTMX},
+			want: `<div><br></div>`,
+		},
+		{
+			name: "boolean attributes",
+			abbr: "btn:d+input[checked required=]+p[title]",
+			opts: Options{},
+			want: `<button disabled></button><input checked required="" type="text" name=""><p title=""></p>`,
+		},
+		{
+			name: "expanded boolean attributes in xhtml",
+			abbr: "btn:d+input[checked]",
+			opts: Options{SelfClosing: SelfClosingXHTML},
+			want: `<button disabled="disabled"></button><input checked="checked" type="text" name="" />`,
+		},
+		{
+			name: "expanded boolean attributes in html",
+			abbr: "select:d",
+			opts: Options{BooleanAttributes: BooleanAttributeExpanded, Quote: QuoteSingle},
+			want: `<select name='' disabled='disabled'></select>`,
+		},
+		{
+			name: "boolean attributes in xml mode",
+			abbr: "option[selected]",
+			opts: Options{Mode: ModeXML},
+			want: `<option selected="selected" />`,
+		},
+		{
+			name: "escaping",
+			abbr: `a[title="Tom & Jerry"]{a < b}`,
+			opts: Options{},
+			want: `<a title="Tom &amp; Jerry" href="#">a &lt; b</a>`,
+		},
+		{
+			name: "raw template expressions",
+			abbr: `a[href="{{ url 'home' }}"]{<%= name %>}`,
+			opts: Options{Raw: true, Quote: QuoteSingle},
+			want: `<a href='{{ url 'home' }}'><%= name %></a>`,
+		},
+		{
+			name: "raw template expressions in text",
+			abbr: `p{{{ .Name }}}+p{a {b} c}`,
+			opts: Options{Raw: true},
+			want: `<p>{{ .Name }}</p><p>a {b} c</p>`,
+		},
+		{
+			name: "snippet tab stops",
+			abbr: "a:link+p",
+			opts: Options{TabStop: TabStopSnippet},
+			want: `<a href="${1:https://}">${2}</a><p>${3}</p>`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, gotErr := Expand(tt.abbr, tt.opts)
+			require.NoError(t, gotErr)
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
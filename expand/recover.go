@@ -0,0 +1,83 @@
+package expand
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// danglingRunes can not end an abbreviation, they are dropped when recovering from errors.
+const danglingRunes = "><+^.#*@$-(~"
+
+// TokenizeRecover tokenizes the longest valid prefix of runes. Unterminated brackets, braces, parentheses and quotes
+// are closed automatically. Everything which had to be fixed or dropped is reported as a warning, with offsets
+// relative to runes. An error is only returned if no valid prefix is found.
+func (l *Lexer) TokenizeRecover(runes []rune) ([]Token, []*SyntaxError, error) {
+	var (
+		warnings []*SyntaxError
+		current  = runes
+		firstErr error
+	)
+
+	for len(current) > 0 {
+		closed, closeWarnings := autoClose(runes, current)
+
+		tokens, _, err := l.Tokenize(closed, false)
+		if err == nil {
+			return tokens, append(warnings, closeWarnings...), nil
+		}
+
+		var syntaxErr *SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			return nil, warnings, err
+		}
+
+		if firstErr == nil {
+			firstErr = err
+		}
+
+		cut := min(syntaxErr.Offset, len(current))
+		warnings = append(warnings, newSyntaxError(runes, cut, syntaxErr))
+
+		// make sure the input shrinks on each iteration
+		if cut == len(current) {
+			cut--
+		}
+
+		current = []rune(strings.TrimRight(string(current[:cut]), danglingRunes))
+	}
+
+	return nil, warnings, firstErr
+}
+
+// autoClose appends the closing characters of unterminated brackets, braces, parentheses and quotes to current,
+// which is a prefix of runes.
+func autoClose(runes, current []rune) ([]rune, []*SyntaxError) {
+	var warnings []*SyntaxError
+
+	_, openers, inQuote := findOutside(current, 0)
+
+	closed := append([]rune{}, current...)
+
+	if inQuote != 0 {
+		closed = append(closed, inQuote)
+		warnings = append(warnings, newSyntaxError(runes, len(current), expected(ErrDirectiveClosingMissing, fmt.Sprintf("%q", inQuote))))
+	}
+
+	for i := len(openers) - 1; i >= 0; i-- {
+		closer := closingBracket
+
+		switch openers[i] {
+		case openingBrace:
+			closer = closingBrace
+		case openingParenthesis:
+			closer = closingParenthesis
+		}
+
+		closed = append(closed, closer)
+		warnings = append(warnings, newSyntaxError(runes, len(current), expected(ErrDirectiveClosingMissing, "'"+string(closer)+"'")))
+	}
+
+	return closed, warnings
+}
@@ -0,0 +1,139 @@
+package expand
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpand_Recover(t *testing.T) {
+	t.Parallel()
+
+	type warning struct {
+		offset   int
+		expected string
+	}
+	tests := []struct {
+		name         string
+		abbr         string
+		want         string
+		wantWarnings []warning
+	}{
+		{
+			name:         "valid",
+			abbr:         "ul>li",
+			want:         `<ul><li></li></ul>`,
+			wantWarnings: nil,
+		},
+		{
+			name: "unknown filter",
+			abbr: "p.a|c|x",
+			want: `<p class="a"></p><!-- /.a -->`,
+			wantWarnings: []warning{
+				{offset: 6, expected: "filter name"},
+			},
+		},
+		{
+			name: "unterminated attribute",
+			abbr: "ul>li.item$*2>a[href=",
+			want: `<ul><li class="item1"><a href=""></a></li><li class="item2"><a href=""></a></li></ul>`,
+			wantWarnings: []warning{
+				{offset: 21, expected: "']'"},
+			},
+		},
+		{
+			name: "unterminated quote",
+			abbr: `a[title="foo`,
+			want: `<a title="foo" href="#"></a>`,
+			wantWarnings: []warning{
+				{offset: 12, expected: `'"'`},
+				{offset: 12, expected: "']'"},
+			},
+		},
+		{
+			name: "unterminated single quote",
+			abbr: `a[title='foo "bar"`,
+			want: `<a title="foo &quot;bar&quot;" href="#"></a>`,
+			wantWarnings: []warning{
+				{offset: 18, expected: `'\''`},
+				{offset: 18, expected: "']'"},
+			},
+		},
+		{
+			name: "unterminated text and group",
+			abbr: "(p{foo",
+			want: `<p>foo</p>`,
+			wantWarnings: []warning{
+				{offset: 6, expected: "'}'"},
+				{offset: 6, expected: "')'"},
+			},
+		},
+		{
+			name: "trailing directive",
+			abbr: "ul>li*3>",
+			want: `<ul><li></li><li></li><li></li></ul>`,
+			wantWarnings: []warning{
+				{offset: 8, expected: "tag name or '('"},
+			},
+		},
+		{
+			name: "incomplete class and repeat",
+			abbr: "ul>li.",
+			want: `<ul><li></li></ul>`,
+			wantWarnings: []warning{
+				{offset: 6, expected: "class name"},
+			},
+		},
+		{
+			name: "invalid rest",
+			abbr: "p>a!+b",
+			want: `<p><a href="#"></a></p>`,
+			wantWarnings: []warning{
+				{offset: 3, expected: "'+', '>', '^' or ')'"},
+			},
+		},
+		{
+			name: "broken inside group",
+			abbr: "(ul>li.)+p",
+			want: `<ul><li></li></ul>`,
+			wantWarnings: []warning{
+				{offset: 7, expected: "class name"},
+				{offset: 6, expected: "')'"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, gotErr := ExpandWithTabStops(tt.abbr, Options{Recover: true})
+			require.NoError(t, gotErr)
+
+			assert.Equal(t, tt.want, got.Output)
+
+			var gotWarnings []warning
+			for _, w := range got.Warnings {
+				gotWarnings = append(gotWarnings, warning{offset: w.Offset, expected: w.Expected})
+			}
+
+			assert.Equal(t, tt.wantWarnings, gotWarnings)
+		})
+	}
+
+	t.Run("nothing valid", func(t *testing.T) {
+		t.Parallel()
+
+		_, gotErr := Expand("%p", Options{Recover: true})
+		require.ErrorIs(t, gotErr, ErrInputTooShort)
+	})
+
+	t.Run("strict mode fails", func(t *testing.T) {
+		t.Parallel()
+
+		_, gotErr := Expand("ul>li.", Options{})
+		require.Error(t, gotErr)
+	})
+}
@@ -0,0 +1,25 @@
+package expand
+
+import "strings"
+
+// Renderer writes the built elements in the syntax of a template language.
+type Renderer interface {
+	Render(builder *strings.Builder, elemList ElemList, counter *Counter, opts Options)
+}
+
+// nolint: gochecknoglobals
+var renderers = map[Syntax]Renderer{
+	SyntaxMarkup: MarkupRenderer{},
+	SyntaxPug:    PugRenderer,
+	SyntaxHAML:   HAMLRenderer,
+	SyntaxSlim:   SlimRenderer,
+}
+
+// blockIndentation is the indentation of syntaxes where nesting is expressed by indentation only.
+func (o Options) blockIndentation() string {
+	if o.Indentation == "" {
+		return DefaultIndentation
+	}
+
+	return o.Indentation
+}
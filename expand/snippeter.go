@@ -1,7 +1,12 @@
-package main
+package expand
+
+import "strings"
 
 type Snippeter struct {
-	mode Mode
+	mode     Mode
+	snippets Snippets
+	// inlineElements overrides the inline-level elements of the mode if not nil, see Options.InlineElements.
+	inlineElements []string
 }
 
 func NewSnippeter(mode Mode) *Snippeter {
@@ -10,6 +15,23 @@ func NewSnippeter(mode Mode) *Snippeter {
 	}
 }
 
+// SetSnippets sets the user-defined snippets, which take precedence over the built-in ones.
+func (s *Snippeter) SetSnippets(snippets Snippets) *Snippeter {
+	s.snippets = snippets
+
+	return s
+}
+
+// SetInlineElements overrides the inline-level elements of the mode, in which omitted tag names become span.
+func (s *Snippeter) SetInlineElements(names []string) *Snippeter {
+	s.inlineElements = names
+
+	return s
+}
+
+// maxSnippetDepth limits how many user snippets may refer to each other, to stop on cycles.
+const maxSnippetDepth = 8
+
 // nolint:gochecknoglobals
 var htmlTagAbbreviations = map[string]string{
 	"bq":    "blockquote",
@@ -45,17 +67,124 @@ var htmlTagAbbreviations = map[string]string{
 	"cmd":   "command",
 }
 
+// subtreeSnippets expand to a tag with children, e.g. ul+ to ul>li.
+//
+// nolint:gochecknoglobals
+var subtreeSnippets = map[string]Snippet{
+	"ul+":       {Name: "ul", Children: "li"},
+	"ol+":       {Name: "ol", Children: "li"},
+	"dl+":       {Name: "dl", Children: "dt+dd"},
+	"map+":      {Name: "map", Children: "area"},
+	"table+":    {Name: "table", Children: "tr>td"},
+	"colgroup+": {Name: "colgroup", Children: "col"},
+	"colg+":     {Name: "colgroup", Children: "col"},
+	"tr+":       {Name: "tr", Children: "td"},
+	"select+":   {Name: "select", Children: "option"},
+	"optgroup+": {Name: "optgroup", Children: "option"},
+	"optg+":     {Name: "optgroup", Children: "option"},
+	"pic+":      {Name: "picture", Children: "source:srcset+img"},
+}
+
+const doctypeHTML = "<!DOCTYPE html>"
+
+// documentSnippetNames are the names of document snippets which aren't valid tag names.
+//
+// nolint:gochecknoglobals
+var documentSnippetNames = []string{"!", "!!!"}
+
+// documentSnippets expand to the html element of a page, see Snippeter.ApplyDocumentSnippets.
+//
+// nolint:gochecknoglobals
+var documentSnippets = map[Mode]Snippet{
+	ModeHTML: {
+		Name:       "html",
+		Attributes: []SnippetAttr{{Name: "lang", Value: "en", Default: true}},
+		Children:   "(head>meta:utf+meta:vp+title{Document})+body",
+	},
+	ModeHTMX: {
+		Name:       "html",
+		Attributes: []SnippetAttr{{Name: "lang", Value: "en", Default: true}},
+		Children:   "(head>meta:utf+meta:vp+title{Document}+script:htmx)+body",
+	},
+}
+
+// implicitTagNames maps parent tag names to the tag name their children get if it was omitted, e.g. ul>.item.
+//
+// nolint:gochecknoglobals
+var implicitTagNames = map[string]string{
+	"p":        "span",
+	"ul":       "li",
+	"ol":       "li",
+	"table":    "tr",
+	"tbody":    "tr",
+	"thead":    "tr",
+	"tfoot":    "tr",
+	"tr":       "td",
+	"colgroup": "col",
+	"select":   "option",
+	"optgroup": "option",
+	"audio":    "source",
+	"video":    "source",
+	"object":   "param",
+	"map":      "area",
+}
+
+// ResolveImplicitTag names tags which were given without a tag name, based on their closest tag ancestor. They become
+// span in inline-level elements, see inlineElements.
+// Ancestors must already be resolved, which is why Walk calls it before walking the children.
+func (s *Snippeter) ResolveImplicitTag(token *TagToken) {
+	if token.Name != "" {
+		return
+	}
+
+	parent := closestTagToken(token.GetParent())
+
+	switch {
+	case parent == nil:
+		token.SetName("div")
+
+	case implicitTagNames[parent.Name] != "":
+		token.SetName(implicitTagNames[parent.Name])
+
+	case isInlineElement(s.mode, s.inlineElements, parent.Name):
+		token.SetName("span")
+
+	default:
+		token.SetName("div")
+	}
+}
+
+// closestTagToken skips groups to find the closest TagToken, it returns nil for top level tokens.
+func closestTagToken(token Token) *TagToken {
+	for token != nil {
+		if tagToken, ok := token.(*TagToken); ok {
+			return tagToken
+		}
+
+		token = token.GetParent()
+	}
+
+	return nil
+}
+
+// Walk applies the snippets to tokens and their descendants. Tokens replaced by a snippet are replaced in place, so
+// that the replacements end up in the children of their parents too.
+//
 // based on https://github.com/emmetio/emmet/blob/master/src/snippets/html.json
 func (s *Snippeter) Walk(tokens ...Token) []Token {
-	for _, token := range tokens {
+	for i, token := range tokens {
 		if tagToken, ok := token.(*TagToken); ok {
-			s.ApplySnippets(tagToken)
-		}
+			s.ResolveImplicitTag(tagToken)
 
-		for _, child := range token.GetChildren() {
-			s.Walk(child)
+			token = s.ApplySnippets(tagToken)
+			if token != tagToken {
+				token.SetParent(tagToken.GetParent())
+				tokens[i] = token
+			}
 		}
 
+		s.Walk(token.GetChildren()...)
+
 		tagToken, ok := token.(*TagToken)
 		if !ok {
 			continue
@@ -69,9 +198,38 @@ func (s *Snippeter) Walk(tokens ...Token) []Token {
 
 // nolint: unparam, ireturn
 func (s *Snippeter) ApplySnippets(token *TagToken) Token {
+	// user snippets may refer to other snippets, e.g. "input:c": "inp[type=checkbox]"
+	for i := 0; i < maxSnippetDepth; i++ {
+		snippet, ok := s.snippets.find(s.mode, token.Name)
+		if !ok || token.inSnippet(token.Name) {
+			break
+		}
+
+		name := token.Name
+		snippet.apply(token, s.mode, name)
+
+		// the snippet overrides the built-in one of the same name
+		if token.Name == name {
+			return token
+		}
+	}
+
+	if _, ok := documentSnippets[s.mode]; ok {
+		if replacement := s.ApplyDocumentSnippets(token); replacement != nil {
+			return replacement
+		}
+	}
+
+	if snippet, ok := subtreeSnippets[token.Name]; ok && s.mode.isHTML() {
+		snippet.apply(token, s.mode, token.Name)
+	}
+
+	// pluses of unknown subtree snippets are ignored, e.g. div+
+	token.SetName(strings.TrimSuffix(token.Name, string(plus)))
+
 	// nolint: exhaustive
 	switch s.mode {
-	case ModeHTML, ModeHTMX:
+	case ModeHTML, ModeHTMX, ModeJSX:
 		if mappedName, ok := htmlTagAbbreviations[token.Name]; ok {
 			token.SetName(mappedName)
 		}
@@ -86,6 +244,41 @@ func (s *Snippeter) ApplySnippets(token *TagToken) Token {
 	return token
 }
 
+// ApplyDocumentSnippets expands the snippets of whole pages: !!! to a doctype, doc to the html element and ! or html:5
+// to both. It returns the replacement of token, or nil if it's not a document snippet. The doctype can't have
+// children, the ones of !!! follow it instead, e.g. !!!>p is the same as !!!+p.
+//
+// nolint: ireturn
+func (s *Snippeter) ApplyDocumentSnippets(token *TagToken) Token {
+	switch token.Name {
+	case "!!!":
+		return documentGroup(token, append([]Token{NewRawToken(doctypeHTML)}, token.Children...)...)
+
+	case "doc":
+		documentSnippets[s.mode].apply(token, s.mode, token.Name)
+
+	case "!", "html:5":
+		documentSnippets[s.mode].apply(token, s.mode, token.Name)
+
+		return documentGroup(token, NewRawToken(doctypeHTML), token)
+	}
+
+	return nil
+}
+
+// documentGroup groups the tokens replacing token, the repeat of token is moved to the group, e.g. !*2 repeats both
+// the doctype and the html element.
+func documentGroup(token *TagToken, tokens ...Token) *GroupToken {
+	repeat := token.Repeat
+	if token.ImplicitRepeat {
+		repeat = 0
+	}
+
+	token.SetRepeat(1)
+
+	return NewGroupToken(1, tokens...).SetRepeat(repeat)
+}
+
 func (s *Snippeter) ApplyHTMXSnippets(token *TagToken) {
 	switch token.Name {
 	case "a:get", "a:post", "a:put", "a:patch", "a:delete":
@@ -555,7 +748,7 @@ func (s *Snippeter) ApplyHTMLSnippets(token *TagToken) {
 
 	case "opt", "option":
 		token.
-			SetName("select").
+			SetName("option").
 			FallbackAttribute(NewAttr("Value", ""))
 
 	case "textarea":
@@ -0,0 +1,449 @@
+package expand
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnippeter_Walk(t *testing.T) {
+	t.Parallel()
+
+	type fields struct {
+		mode Mode
+	}
+	type args struct {
+		tokens []Token
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		want   *TagToken
+	}{
+		{
+			name: "a",
+			fields: fields{
+				mode: ModeHTML,
+			},
+			args: args{
+				tokens: []Token{
+					NewTagToken("a", 1),
+				},
+			},
+			want: NewTagToken("a", 1).
+				AddAttribute(NewDefaultAttr("href", "#")),
+		},
+		{
+			name: "a - overwrite",
+			fields: fields{
+				mode: ModeHTML,
+			},
+			args: args{
+				tokens: []Token{
+					NewTagToken("a", 1).
+						AddAttribute(NewDefaultAttr("href", "foo")),
+				},
+			},
+			want: NewTagToken("a", 1).
+				AddAttribute(NewDefaultAttr("href", "foo")),
+		},
+		{
+			name: "area",
+			fields: fields{
+				mode: ModeHTML,
+			},
+			args: args{
+				tokens: []Token{
+					NewTagToken("area", 1),
+				},
+			},
+			want: NewTagToken("area", 1).
+				AddAttribute(NewDefaultAttr("coords", "")).
+				AddAttribute(NewDefaultAttr("href", "")).
+				AddAttribute(NewDefaultAttr("alt", "")).
+				AddAttribute(NewDefaultAttr("shape", "")),
+		},
+		{
+			name: "area:d",
+			fields: fields{
+				mode: ModeHTML,
+			},
+			args: args{
+				tokens: []Token{
+					NewTagToken("area:d", 1),
+				},
+			},
+			want: NewTagToken("area", 1).
+				AddAttribute(NewDefaultAttr("coords", "")).
+				AddAttribute(NewDefaultAttr("href", "")).
+				AddAttribute(NewDefaultAttr("alt", "")).
+				AddAttribute(NewAttr("shape", "default")),
+		},
+		{
+			name: "bq",
+			fields: fields{
+				mode: ModeHTML,
+			},
+			args: args{
+				tokens: []Token{
+					NewTagToken("bq", 1),
+				},
+			},
+			want: NewTagToken("blockquote", 1),
+		},
+		{
+			name: "ifr",
+			fields: fields{
+				mode: ModeHTML,
+			},
+			args: args{
+				tokens: []Token{
+					NewTagToken("ifr", 1),
+				},
+			},
+			want: NewTagToken("iframe", 1).
+				AddAttribute(NewDefaultAttr("src", "")).
+				AddAttribute(NewDefaultAttr("frameborder", "0")),
+		},
+		{
+			name: "input:q",
+			fields: fields{
+				mode: ModeHTMX,
+			},
+			args: args{
+				tokens: []Token{
+					NewTagToken("input:q", 1),
+				},
+			},
+			want: NewTagToken("input", 1).
+				AddAttribute(NewAttr("name", "q")).
+				AddAttribute(NewAttr("type", "search")).
+				AddAttribute(NewAttr("hx-get", "")).
+				AddAttribute(NewDefaultAttr("hx-trigger", "keyup changed delay:500ms")).
+				AddAttribute(NewAttr("hx-target", "")).
+				AddAttribute(NewAttr("hx-swap", "innerHTML")).
+				AddAttribute(NewAttr("placeholder", "")),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			sut := NewSnippeter(tt.fields.mode)
+
+			got := sut.Walk(tt.args.tokens...)
+
+			assert.NotNil(t, got)
+			assert.Len(t, got, 1)
+			assert.IsType(t, &TagToken{}, got[0])
+
+			g, ok := got[0].(*TagToken)
+			assert.True(t, ok)
+			assert.Equal(t, tt.want.Name, g.Name)
+			assert.Equal(t, tt.want.ID, g.ID)
+			assert.Equal(t, tt.want.Classes, g.Classes)
+			assert.Equal(t, tt.want.Attributes, g.Attributes)
+		})
+	}
+}
+
+func TestSnippeter_Walk_SnippetCycles(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		snippets map[string]Snippet
+		abbr     string
+		want     string
+	}{
+		{
+			name:     "snippet in its own children",
+			snippets: map[string]Snippet{"box": {Name: "div", Children: "box"}},
+			abbr:     "box>span",
+			want:     "div(box(span))",
+		},
+		{
+			name:     "snippet overriding a tag in its own children",
+			snippets: map[string]Snippet{"div": {Name: "div", Children: "div"}},
+			abbr:     "div",
+			want:     "div(div)",
+		},
+		{
+			name: "snippets in each other's children",
+			snippets: map[string]Snippet{
+				"box":  {Name: "div", Children: "p>card"},
+				"card": {Name: "section", Children: "box"},
+			},
+			abbr: "box",
+			want: "div(p(section(box)))",
+		},
+		{
+			name:     "snippets given in the abbreviation are expanded",
+			snippets: map[string]Snippet{"box": {Name: "div", Children: "p"}},
+			abbr:     "box>box",
+			want:     "div(p(div(p)))",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tokens, _, err := NewLexer(ModeHTML).Tokenize([]rune(tt.abbr), false)
+			require.NoError(t, err)
+
+			got := NewSnippeter(ModeHTML).SetSnippets(Snippets{ModeHTML: tt.snippets}).Walk(tokens...)
+
+			assert.Equal(t, tt.want, tagTree(got))
+		})
+	}
+}
+
+// tagTree writes the tag names of tokens with their children in parentheses, e.g. ul(li,li).
+func tagTree(tokens []Token) string {
+	names := make([]string, 0, len(tokens))
+
+	for _, token := range tokens {
+		name := ""
+		if tagToken, ok := token.(*TagToken); ok {
+			name = tagToken.Name
+		}
+
+		if children := token.GetChildren(); len(children) > 0 {
+			name += "(" + tagTree(children) + ")"
+		}
+
+		names = append(names, name)
+	}
+
+	return strings.Join(names, ",")
+}
+
+func TestSnippeter_ResolveImplicitTag(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		parent Token
+		want   string
+	}{
+		{
+			name: "top level",
+			want: "div",
+		},
+		{
+			name:   "list",
+			parent: NewTagToken("ul", 1),
+			want:   "li",
+		},
+		{
+			name:   "table",
+			parent: NewTagToken("table", 1),
+			want:   "tr",
+		},
+		{
+			name:   "table row",
+			parent: NewTagToken("tr", 1),
+			want:   "td",
+		},
+		{
+			name:   "select",
+			parent: NewTagToken("select", 1),
+			want:   "option",
+		},
+		{
+			name:   "inline parent",
+			parent: NewTagToken("em", 1),
+			want:   "span",
+		},
+		{
+			name:   "block parent",
+			parent: NewTagToken("section", 1),
+			want:   "div",
+		},
+		{
+			name:   "group is skipped",
+			parent: NewTagToken("ol", 1).AddChildren(NewGroupToken(1)).GetChildren()[0],
+			want:   "li",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			token := NewTagToken("", 1)
+			if tt.parent != nil {
+				tt.parent.AddChildren(token)
+			}
+
+			NewSnippeter(ModeHTML).ResolveImplicitTag(token)
+
+			assert.Equal(t, tt.want, token.Name)
+		})
+	}
+}
+
+func TestExpand_SubtreeSnippets(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		abbr string
+		want string
+	}{
+		{
+			name: "list",
+			abbr: "ul+",
+			want: `<ul><li></li></ul>`,
+		},
+		{
+			name: "table",
+			abbr: "table+",
+			want: `<table><tr><td></td></tr></table>`,
+		},
+		{
+			name: "siblings",
+			abbr: "dl+",
+			want: `<dl><dt></dt><dd></dd></dl>`,
+		},
+		{
+			name: "classes, attributes and repeat are kept",
+			abbr: "ol.steps[start=3]+*2",
+			want: `<ol start="3" class="steps"><li></li></ol><ol start="3" class="steps"><li></li></ol>`,
+		},
+		{
+			name: "children go into the deepest last element",
+			abbr: "table+>.cell{x}",
+			want: `<table><tr><td><div class="cell">x</div></td></tr></table>`,
+		},
+		{
+			name: "followed by a sibling",
+			abbr: "(tr+)+p",
+			want: `<tr><td></td></tr><p></p>`,
+		},
+		{
+			name: "unknown subtree snippet",
+			abbr: "div+",
+			want: `<div></div>`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Expand(tt.abbr, Options{Mode: ModeHTML})
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestExpand_DocumentSnippets(t *testing.T) {
+	t.Parallel()
+
+	const (
+		head = `<head><meta http-equiv="Content-Type" content="text/html;charset=UTF-8">` +
+			`<meta name="viewport" content="width=device-width, user-scalable=no, initial-scale=1.0, maximum-scale=1.0, minimum-scale=1.0">` +
+			`<title>Document</title>`
+		htmx = `<script src="https://unpkg.com/htmx.org@1.9.10"></script>`
+	)
+
+	tests := []struct {
+		name string
+		abbr string
+		opts Options
+		want string
+	}{
+		{
+			name: "doctype",
+			abbr: "!!!",
+			opts: Options{Mode: ModeHTML},
+			want: `<!DOCTYPE html>`,
+		},
+		{
+			name: "document",
+			abbr: "!",
+			opts: Options{Mode: ModeHTML},
+			want: `<!DOCTYPE html><html lang="en">` + head + `</head><body></body></html>`,
+		},
+		{
+			name: "html:5",
+			abbr: "html:5",
+			opts: Options{Mode: ModeHTML},
+			want: `<!DOCTYPE html><html lang="en">` + head + `</head><body></body></html>`,
+		},
+		{
+			name: "html element only",
+			abbr: "doc",
+			opts: Options{Mode: ModeHTML},
+			want: `<html lang="en">` + head + `</head><body></body></html>`,
+		},
+		{
+			name: "lang and children are kept",
+			abbr: "![lang=de]>main",
+			opts: Options{Mode: ModeHTML},
+			want: `<!DOCTYPE html><html lang="de">` + head + `</head><body><main></main></body></html>`,
+		},
+		{
+			name: "htmx",
+			abbr: "!",
+			opts: Options{Mode: ModeHTMX},
+			want: `<!DOCTYPE html><html lang="en">` + head + htmx + `</head><body></body></html>`,
+		},
+		{
+			name: "multiline",
+			abbr: "!!!+p",
+			opts: Options{Mode: ModeHTML, Indentation: "  ", Multiline: true},
+			want: "<!DOCTYPE html>\n<p></p>",
+		},
+		{
+			name: "children of the doctype follow it",
+			abbr: "!!!>p+a",
+			opts: Options{Mode: ModeHTML},
+			want: `<!DOCTYPE html><p></p><a href="#"></a>`,
+		},
+		{
+			name: "repeated doctype",
+			abbr: "!!!*2",
+			opts: Options{Mode: ModeHTML},
+			want: `<!DOCTYPE html><!DOCTYPE html>`,
+		},
+		{
+			name: "repeated document",
+			abbr: "!*2",
+			opts: Options{Mode: ModeHTML},
+			want: `<!DOCTYPE html><html lang="en">` + head + `</head><body></body></html>` +
+				`<!DOCTYPE html><html lang="en">` + head + `</head><body></body></html>`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Expand(tt.abbr, tt.opts)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("not supported in xml", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Expand("!", Options{Mode: ModeXML})
+		require.ErrorIs(t, err, ErrInvalidTagName)
+	})
+}
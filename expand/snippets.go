@@ -0,0 +1,337 @@
+package expand
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	ErrUnknownSnippetFormat = errors.New("unknown snippet file format")
+	ErrInvalidSnippet       = errors.New("invalid snippet")
+)
+
+// SnippetFileNames are the names snippet files are looked for by default, in order.
+//
+// nolint: gochecknoglobals
+var SnippetFileNames = []string{"snippets.json", "snippets.yaml", "snippets.yml", "snippets.toml"}
+
+// Snippet is a user-defined snippet. Like the built-in ones, it can rename the tag and give it an id, classes,
+// attributes and text, none of which override the ones given in the abbreviation.
+type Snippet struct {
+	// Name is the tag name the snippet expands to, the snippet keeps its own name if empty.
+	Name       string        `json:"name,omitempty" yaml:"name,omitempty" toml:"name,omitempty"`
+	ID         string        `json:"id,omitempty" yaml:"id,omitempty" toml:"id,omitempty"`
+	Classes    []string      `json:"classes,omitempty" yaml:"classes,omitempty" toml:"classes,omitempty"`
+	Attributes []SnippetAttr `json:"attributes,omitempty" yaml:"attributes,omitempty" toml:"attributes,omitempty"`
+	Text       string        `json:"text,omitempty" yaml:"text,omitempty" toml:"text,omitempty"`
+	// Children is the abbreviation of the subtree the element gets, e.g. "tr>td" for table+. The children given in
+	// the abbreviation are moved into the deepest last element of the subtree.
+	Children string `json:"children,omitempty" yaml:"children,omitempty" toml:"children,omitempty"`
+}
+
+// SnippetAttr is an attribute of a Snippet, a Default value is a placeholder and becomes a tab stop, written between
+// Prefix and Suffix, e.g. http:// and .css. Boolean attributes are rendered without a value, e.g. disabled.
+type SnippetAttr struct {
+	Name    string `json:"name" yaml:"name" toml:"name"`
+	Value   string `json:"value,omitempty" yaml:"value,omitempty" toml:"value,omitempty"`
+	Default bool   `json:"default,omitempty" yaml:"default,omitempty" toml:"default,omitempty"`
+	Prefix  string `json:"prefix,omitempty" yaml:"prefix,omitempty" toml:"prefix,omitempty"`
+	Suffix  string `json:"suffix,omitempty" yaml:"suffix,omitempty" toml:"suffix,omitempty"`
+	Boolean bool   `json:"boolean,omitempty" yaml:"boolean,omitempty" toml:"boolean,omitempty"`
+}
+
+// Snippets holds user-defined snippets by mode and name. HTML snippets apply in HTMX and JSX mode too.
+type Snippets map[Mode]map[string]Snippet
+
+// LoadSnippets reads a JSON, YAML or TOML snippet file, the format is decided by the file extension.
+func LoadSnippets(path string) (Snippets, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read snippet file")
+	}
+
+	snippets := Snippets{}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+
+		err = decoder.Decode(&snippets)
+
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+
+		err = decoder.Decode(&snippets)
+
+	case ".toml":
+		var meta toml.MetaData
+
+		meta, err = toml.Decode(string(data), &snippets)
+		if err == nil && len(meta.Undecoded()) > 0 {
+			err = errors.Errorf("unknown field %q", meta.Undecoded()[0].String())
+		}
+
+	default:
+		return nil, errors.Wrapf(ErrUnknownSnippetFormat, "file: %q", path)
+	}
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse snippet file %q", path)
+	}
+
+	return snippets, nil
+}
+
+// Merge returns the snippets of s overridden by the ones of other with the same mode and name.
+func (s Snippets) Merge(other Snippets) Snippets {
+	merged := Snippets{}
+
+	for _, snippets := range []Snippets{s, other} {
+		for mode, byName := range snippets {
+			if merged[mode] == nil {
+				merged[mode] = map[string]Snippet{}
+			}
+
+			for name, snippet := range byName {
+				merged[mode][name] = snippet
+			}
+		}
+	}
+
+	return merged
+}
+
+func (s Snippets) Validate() error {
+	for mode, byName := range s {
+		if !mode.isKnown() {
+			return errors.Wrapf(ErrUnknownMode, "snippet mode: %q", mode)
+		}
+
+		l := NewLexer(mode)
+
+		for name, snippet := range byName {
+			if err := l.validSnippetName(name); err != nil {
+				return errors.Wrapf(ErrInvalidTagName, "snippet %q in mode %q", name, mode)
+			}
+
+			if _, err := l.validTagName([]rune(snippet.Name)); snippet.Name != "" && err != nil {
+				return errors.Wrapf(ErrInvalidTagName, "name of snippet %q in mode %q: %q", name, mode, snippet.Name)
+			}
+
+			for _, attr := range snippet.Attributes {
+				if attr.Name == "" {
+					return errors.Wrapf(ErrInvalidSnippet, "attribute without a name in snippet %q in mode %q", name, mode)
+				}
+			}
+
+			if _, _, err := l.Tokenize([]rune(snippet.Children), false); snippet.Children != "" && err != nil {
+				return errors.Wrapf(ErrInvalidSnippet, "children of snippet %q in mode %q: %s", name, mode, err)
+			}
+		}
+
+		if cycle := s.findCycle(mode); cycle != nil {
+			return errors.Wrapf(ErrInvalidSnippet, "snippets in mode %q refer to each other: %s", mode, strings.Join(cycle, " > "))
+		}
+	}
+
+	return nil
+}
+
+// findCycle returns the names of snippets in mode which end up in their own tag name or children, e.g. box > box for
+// a box snippet with box children, nil if there are none.
+func (s Snippets) findCycle(mode Mode) []string {
+	names := make([]string, 0, len(s[mode]))
+	for name := range s[mode] {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var (
+		done = map[string]bool{}
+		path []string
+	)
+
+	var visit func(name string) []string
+
+	visit = func(name string) []string {
+		for i, visited := range path {
+			if visited == name {
+				return append(append([]string{}, path[i:]...), name)
+			}
+		}
+
+		if done[name] {
+			return nil
+		}
+
+		path = append(path, name)
+
+		for _, ref := range s.references(mode, name) {
+			if cycle := visit(ref); cycle != nil {
+				return cycle
+			}
+		}
+
+		path = path[:len(path)-1]
+		done[name] = true
+
+		return nil
+	}
+
+	for _, name := range names {
+		if cycle := visit(name); cycle != nil {
+			return cycle
+		}
+	}
+
+	return nil
+}
+
+// references returns the names of the snippets the snippet called name refers to by its tag name or children.
+func (s Snippets) references(mode Mode, name string) []string {
+	snippet, _ := s.find(mode, name)
+
+	var refs []string
+
+	if _, ok := s.find(mode, snippet.Name); ok && snippet.Name != name {
+		refs = append(refs, snippet.Name)
+	}
+
+	children, _, err := NewLexer(mode).Tokenize([]rune(snippet.Children), false)
+	if snippet.Children == "" || err != nil {
+		return refs
+	}
+
+	var walk func(tokens []Token)
+
+	walk = func(tokens []Token) {
+		for _, token := range tokens {
+			if tagToken, ok := token.(*TagToken); ok {
+				if _, ok := s.find(mode, tagToken.Name); ok {
+					refs = append(refs, tagToken.Name)
+				}
+			}
+
+			walk(token.GetChildren())
+		}
+	}
+
+	walk(children)
+
+	return refs
+}
+
+// validSnippetName checks name against the tag name grammar, snippets expanding to a subtree may end in a plus.
+func (l *Lexer) validSnippetName(name string) error {
+	_, err := l.validTagName([]rune(strings.TrimSuffix(name, string(plus))))
+
+	return err
+}
+
+// find returns the snippet called name in mode, HTMX and JSX mode fall back to the HTML snippets.
+func (s Snippets) find(mode Mode, name string) (Snippet, bool) {
+	if snippet, ok := s[mode][name]; ok {
+		return snippet, true
+	}
+
+	if mode == ModeHTMX || mode == ModeJSX {
+		snippet, ok := s[ModeHTML][name]
+
+		return snippet, ok
+	}
+
+	return Snippet{}, false
+}
+
+// apply is safe to call repeatedly, as Snippeter.Walk does, because nothing is added twice. The snippet is found by
+// name, which is not applied again to the children it gives.
+func (s Snippet) apply(token *TagToken, mode Mode, name string) {
+	if s.Name != "" {
+		token.SetName(s.Name)
+	}
+
+	if s.ID != "" && token.ID == nil {
+		token.SetID(NewID(s.ID))
+	}
+
+	for _, class := range s.Classes {
+		if !token.hasClass(class) {
+			token.AddClass(NewClass(class))
+		}
+	}
+
+	for _, attr := range s.Attributes {
+		switch {
+		case attr.Boolean:
+			token.FallbackAttribute(NewAttr(attr.Name, "").HasNoEqualSign().SetBoolean())
+		case attr.Default:
+			token.FallbackAttribute(NewDefaultAttr(attr.Name, attr.Value).SetDefaultAffixes(attr.Prefix, attr.Suffix))
+		default:
+			token.FallbackAttribute(NewAttr(attr.Name, attr.Value))
+		}
+	}
+
+	if s.Text != "" && token.Text.IsEmpty() {
+		token.SetText(NewText(s.Text))
+	}
+
+	if s.Children != "" && !token.expanded {
+		s.expand(token, mode, name)
+	}
+}
+
+// expand gives token the children of the snippet, the ones it already had are moved into the deepest last one. The
+// children are marked as part of the snippet called name, so that it can't expand inside its own subtree.
+func (s Snippet) expand(token *TagToken, mode Mode, name string) {
+	children, _, err := NewLexer(mode).Tokenize([]rune(s.Children), false)
+	if err != nil {
+		return
+	}
+
+	markSnippet(children, append(append([]string{}, token.snippets...), name))
+
+	original := token.Children
+	token.Children = nil
+	token.AddChildren(children...)
+
+	if len(original) > 0 {
+		deepestLastToken(children).AddChildren(original...)
+	}
+
+	token.expanded = true
+}
+
+// markSnippet marks the tags of tokens and their descendants as part of the children of snippets.
+func markSnippet(tokens []Token, snippets []string) {
+	for _, token := range tokens {
+		if tagToken, ok := token.(*TagToken); ok {
+			tagToken.snippets = snippets
+		}
+
+		markSnippet(token.GetChildren(), snippets)
+	}
+}
+
+// deepestLastToken follows the last token of each level down to the deepest one.
+//
+// nolint: ireturn
+func deepestLastToken(tokens []Token) Token {
+	last := tokens[len(tokens)-1]
+
+	if len(last.GetChildren()) == 0 {
+		return last
+	}
+
+	return deepestLastToken(last.GetChildren())
+}
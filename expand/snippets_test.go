@@ -0,0 +1,321 @@
+package expand
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadSnippets(t *testing.T) {
+	t.Parallel()
+
+	want := Snippets{
+		ModeHTML: {
+			"card": Snippet{
+				Name:       "div",
+				Classes:    []string{"card"},
+				Attributes: []SnippetAttr{{Name: "role", Value: "region"}, {Name: "aria-label", Default: true}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		fileName string
+		content  string
+		want     Snippets
+		wantErr  error
+	}{
+		{
+			name:     "json",
+			fileName: "snippets.json",
+			content:  `{"html": {"card": {"name": "div", "classes": ["card"], "attributes": [{"name": "role", "value": "region"}, {"name": "aria-label", "default": true}]}}}`,
+			want:     want,
+		},
+		{
+			name:     "yaml",
+			fileName: "snippets.yaml",
+			content: `html:
+  card:
+    name: div
+    classes: [card]
+    attributes:
+      - name: role
+        value: region
+      - name: aria-label
+        default: true
+`,
+			want: want,
+		},
+		{
+			name:     "toml",
+			fileName: "snippets.toml",
+			content: `[html.card]
+name = "div"
+classes = ["card"]
+attributes = [{name = "role", value = "region"}, {name = "aria-label", default = true}]
+`,
+			want: want,
+		},
+		{
+			name:     "unknown format",
+			fileName: "snippets.ini",
+			content:  "",
+			wantErr:  ErrUnknownSnippetFormat,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), tt.fileName)
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o600))
+
+			got, err := LoadSnippets(path)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLoadSnippets_UnknownField(t *testing.T) {
+	t.Parallel()
+
+	for _, fileName := range []string{"snippets.json", "snippets.yaml", "snippets.toml"} {
+		fileName := fileName
+
+		t.Run(fileName, func(t *testing.T) {
+			t.Parallel()
+
+			content := map[string]string{
+				"snippets.json": `{"html": {"card": {"tag": "div"}}}`,
+				"snippets.yaml": "html:\n  card:\n    tag: div\n",
+				"snippets.toml": "[html.card]\ntag = \"div\"\n",
+			}[fileName]
+
+			path := filepath.Join(t.TempDir(), fileName)
+			require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+			_, err := LoadSnippets(path)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestSnippets_Merge(t *testing.T) {
+	t.Parallel()
+
+	base := Snippets{
+		ModeHTML: {"card": Snippet{Name: "div"}, "btn:x": Snippet{Name: "button"}},
+	}
+	override := Snippets{
+		ModeHTML: {"card": Snippet{Name: "article"}},
+		ModeXML:  {"item": Snippet{Name: "entry"}},
+	}
+
+	got := base.Merge(override)
+
+	assert.Equal(t, Snippets{
+		ModeHTML: {"card": Snippet{Name: "article"}, "btn:x": Snippet{Name: "button"}},
+		ModeXML:  {"item": Snippet{Name: "entry"}},
+	}, got)
+	assert.Equal(t, "div", base[ModeHTML]["card"].Name)
+}
+
+func TestSnippets_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		sut     Snippets
+		wantErr error
+	}{
+		{
+			name: "valid",
+			sut:  Snippets{ModeHTML: {"my-card": Snippet{Name: "div"}}, ModeXML: {"xs:item": Snippet{}}},
+		},
+		{
+			name:    "unknown mode",
+			sut:     Snippets{"jade": {"card": Snippet{}}},
+			wantErr: ErrUnknownMode,
+		},
+		{
+			name:    "invalid snippet name",
+			sut:     Snippets{ModeHTML: {"1card": Snippet{}}},
+			wantErr: ErrInvalidTagName,
+		},
+		{
+			name:    "invalid tag name",
+			sut:     Snippets{ModeHTML: {"card": Snippet{Name: "Card-Box"}}},
+			wantErr: ErrInvalidTagName,
+		},
+		{
+			name:    "invalid children",
+			sut:     Snippets{ModeHTML: {"card+": Snippet{Children: "h2."}}},
+			wantErr: ErrInvalidSnippet,
+		},
+		{
+			name:    "attribute without a name",
+			sut:     Snippets{ModeHTML: {"card": Snippet{Attributes: []SnippetAttr{{Value: "x"}}}}},
+			wantErr: ErrInvalidSnippet,
+		},
+		{
+			name: "snippets referring to each other without a cycle",
+			sut: Snippets{ModeHTML: {
+				"inp":     Snippet{Name: "input", Attributes: []SnippetAttr{{Name: "name"}}},
+				"input:c": Snippet{Name: "inp"},
+				"form+":   Snippet{Name: "form", Children: "inp+input:c"},
+			}},
+		},
+		{
+			name:    "snippet in its own children",
+			sut:     Snippets{ModeHTML: {"box": Snippet{Name: "div", Children: "box"}}},
+			wantErr: ErrInvalidSnippet,
+		},
+		{
+			name:    "snippet overriding a tag in its own children",
+			sut:     Snippets{ModeHTML: {"div": Snippet{Name: "div", Children: "div>p"}}},
+			wantErr: ErrInvalidSnippet,
+		},
+		{
+			name: "snippets in each other's children",
+			sut: Snippets{ModeHTML: {
+				"box":  Snippet{Children: "p>card"},
+				"card": Snippet{Name: "section", Children: "(h2+box)*2"},
+			}},
+			wantErr: ErrInvalidSnippet,
+		},
+		{
+			name: "snippets renamed to each other",
+			sut: Snippets{ModeHTML: {
+				"box":  Snippet{Name: "card"},
+				"card": Snippet{Name: "box"},
+			}},
+			wantErr: ErrInvalidSnippet,
+		},
+		{
+			name:    "htmx snippet in the children of an html one",
+			sut:     Snippets{ModeHTML: {"box": Snippet{Children: "card"}}, ModeHTMX: {"card": Snippet{Children: "box"}}},
+			wantErr: ErrInvalidSnippet,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.sut.Validate()
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestExpand_Snippets(t *testing.T) {
+	t.Parallel()
+
+	snippets := Snippets{
+		ModeHTML: {
+			"card": Snippet{
+				Name:       "div",
+				Classes:    []string{"card"},
+				Attributes: []SnippetAttr{{Name: "role", Value: "region"}},
+				Text:       "Card",
+			},
+			"a":     Snippet{Attributes: []SnippetAttr{{Name: "href", Value: "/"}}},
+			"menu+": Snippet{Name: "nav", Classes: []string{"menu"}, Children: "ul>li.item$*2"},
+		},
+		ModeHTMX: {
+			"card": Snippet{Name: "div", Attributes: []SnippetAttr{{Name: "hx-get", Value: "/card"}}},
+		},
+		ModeXML: {
+			"item": Snippet{Name: "entry", ID: "first"},
+		},
+	}
+
+	tests := []struct {
+		name string
+		abbr string
+		mode Mode
+		want string
+	}{
+		{
+			name: "user snippet",
+			abbr: "card",
+			mode: ModeHTML,
+			want: `<div role="region" class="card">Card</div>`,
+		},
+		{
+			name: "abbreviation wins over the snippet",
+			abbr: "card.card.big[role=note]{Hi}",
+			mode: ModeHTML,
+			want: `<div role="note" class="card big">Hi</div>`,
+		},
+		{
+			name: "subtree snippet",
+			abbr: "menu+>a",
+			mode: ModeHTML,
+			want: `<nav class="menu"><ul><li class="item1"><a href="/"></a></li><li class="item2"><a href="/"></a></li></ul></nav>`,
+		},
+		{
+			name: "user snippet overrides built-in one",
+			abbr: "a",
+			mode: ModeHTML,
+			want: `<a href="/"></a>`,
+		},
+		{
+			name: "htmx snippet overrides html one",
+			abbr: "card",
+			mode: ModeHTMX,
+			want: `<div hx-get="/card"></div>`,
+		},
+		{
+			name: "html snippets apply in htmx mode",
+			abbr: "a",
+			mode: ModeHTMX,
+			want: `<a href="/"></a>`,
+		},
+		{
+			name: "xml snippet",
+			abbr: "item",
+			mode: ModeXML,
+			want: `<entry id="first" />`,
+		},
+		{
+			name: "snippets are scoped to their mode",
+			abbr: "card",
+			mode: ModeXML,
+			want: `<card />`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Expand(tt.abbr, Options{Mode: tt.mode, Snippets: snippets})
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package expand
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Markers used to find tab stops in the rendered output. They are taken from the Unicode private use area,
+// therefore they are not expected to show up in abbreviations.
+const (
+	tabStopStart       = '\uE000'
+	tabStopPlaceholder = '\uE001'
+	tabStopEnd         = '\uE002'
+)
+
+// tabStopMarked is an internal style used to collect tab stop positions.
+const tabStopMarked TabStopStyle = "marked"
+
+// TabStop is a tab stop in the expanded output. Offset and Length are measured in runes.
+type TabStop struct {
+	Index       int
+	Offset      int
+	Length      int
+	Placeholder string
+}
+
+// Result is an expansion with its tab stops and the position where the cursor should be placed. Warnings are only
+// reported if Options.Recover is set.
+type Result struct {
+	Output   string
+	TabStops []TabStop
+	Cursor   int
+	Warnings []*SyntaxError
+}
+
+// ExpandWithTabStops works like Expand, but returns the output free of tab stop markers and reports
+// the tab stops separately. TabStop and TabStopWrapper options are ignored.
+func ExpandWithTabStops(abbr string, opts Options) (Result, error) {
+	opts.TabStop = ""
+
+	if err := opts.Validate(); err != nil {
+		return Result{}, errors.Wrap(err, ErrInvalidOptionsMsg)
+	}
+
+	opts = opts.withDefaults()
+	opts.TabStop = tabStopMarked
+
+	rawResult, warnings, err := render(abbr, opts)
+	if err != nil {
+		return Result{}, err
+	}
+
+	result := extractTabStops(rawResult)
+	result.Warnings = warnings
+
+	return result, nil
+}
+
+func markTabStop(count int, placeholder string) string {
+	return string(tabStopStart) + strconv.Itoa(count) + string(tabStopPlaceholder) + placeholder + string(tabStopEnd)
+}
+
+func extractTabStops(raw string) Result {
+	var (
+		builder  strings.Builder
+		tabStops []TabStop
+		offset   int
+		runes    = []rune(raw)
+	)
+
+	for pos := 0; pos < len(runes); pos++ {
+		if runes[pos] != tabStopStart {
+			builder.WriteRune(runes[pos])
+			offset++
+
+			continue
+		}
+
+		tabStop := TabStop{Offset: offset}
+
+		for pos++; pos < len(runes) && runes[pos] != tabStopPlaceholder; pos++ {
+			tabStop.Index = tabStop.Index*10 + int(runes[pos]-'0') // nolint: gomnd
+		}
+
+		for pos++; pos < len(runes) && runes[pos] != tabStopEnd; pos++ {
+			builder.WriteRune(runes[pos])
+			tabStop.Placeholder += string(runes[pos])
+			tabStop.Length++
+			offset++
+		}
+
+		tabStops = append(tabStops, tabStop)
+	}
+
+	result := Result{
+		Output:   builder.String(),
+		TabStops: tabStops,
+		Cursor:   offset,
+	}
+
+	if len(tabStops) > 0 {
+		result.Cursor = tabStops[0].Offset
+	}
+
+	return result
+}
@@ -0,0 +1,104 @@
+package expand
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandWithTabStops(t *testing.T) {
+	t.Parallel()
+
+	t.Run("error position", func(t *testing.T) {
+		t.Parallel()
+
+		_, gotErr := ExpandWithTabStops("ul>li.", Options{})
+		require.Error(t, gotErr)
+
+		var syntaxErr *SyntaxError
+
+		require.ErrorAs(t, gotErr, &syntaxErr)
+		assert.Equal(t, 6, syntaxErr.Offset)
+	})
+
+	tests := []struct {
+		name string
+		abbr string
+		opts Options
+		want Result
+	}{
+		{
+			name: "no tab stops",
+			abbr: "br",
+			opts: Options{TabStopWrapper: "$"},
+			want: Result{
+				Output: "<br>",
+				Cursor: 4,
+			},
+		},
+		{
+			name: "void elements stay self-closed, only their attributes get tab stops",
+			abbr: "div>br+img+input",
+			opts: Options{},
+			want: Result{
+				Output: `<div><br><img src="" alt=""><input type="text" name=""></div>`,
+				TabStops: []TabStop{
+					{Index: 1, Offset: 19, Length: 0, Placeholder: ""},
+					{Index: 2, Offset: 26, Length: 0, Placeholder: ""},
+					{Index: 3, Offset: 53, Length: 0, Placeholder: ""},
+				},
+				Cursor: 19,
+			},
+		},
+		{
+			name: "empty xml elements stay self-closed",
+			abbr: "item[id=]+item",
+			opts: Options{Mode: ModeXML},
+			want: Result{
+				Output:   `<item id="" /><item />`,
+				TabStops: []TabStop{{Index: 1, Offset: 10, Length: 0, Placeholder: ""}},
+				Cursor:   10,
+			},
+		},
+		{
+			name: "placeholders",
+			abbr: "a:link+p",
+			opts: Options{TabStop: TabStopSnippet},
+			want: Result{
+				Output: `<a href="https://"></a><p></p>`,
+				TabStops: []TabStop{
+					{Index: 1, Offset: 9, Length: 8, Placeholder: "https://"},
+					{Index: 2, Offset: 19, Length: 0, Placeholder: ""},
+					{Index: 3, Offset: 26, Length: 0, Placeholder: ""},
+				},
+				Cursor: 9,
+			},
+		},
+		{
+			name: "multiline, multibyte text",
+			abbr: "ul>li{árvíztűrő}+li",
+			opts: Options{Indentation: "  ", Multiline: true},
+			want: Result{
+				Output: "<ul>\n  <li>árvíztűrő</li>\n  <li></li>\n</ul>",
+				TabStops: []TabStop{
+					{Index: 1, Offset: 20, Length: 0, Placeholder: ""},
+					{Index: 2, Offset: 32, Length: 0, Placeholder: ""},
+				},
+				Cursor: 20,
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, gotErr := ExpandWithTabStops(tt.abbr, tt.opts)
+			require.NoError(t, gotErr)
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
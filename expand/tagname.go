@@ -0,0 +1,148 @@
+package expand
+
+import (
+	"slices"
+	"unicode"
+)
+
+// allowedTagName lists the characters the lexer collects as a tag name, the result is checked by Lexer.validTagName.
+// Dots are part of both the XML NameChar production and the custom element grammar, but they always start a class
+// in abbreviations, e.g. my-card.active.
+func allowedTagName(r rune) bool {
+	return r != dotSign && isXMLNameChar(r)
+}
+
+// isXMLNameStartChar implements the NameStartChar production of https://www.w3.org/TR/xml/#NT-NameStartChar
+//
+// nolint: cyclop
+func isXMLNameStartChar(r rune) bool {
+	switch {
+	case r == colon, r == '_', r >= 'A' && r <= 'Z', r >= 'a' && r <= 'z':
+		return true
+	case r >= 0xC0 && r <= 0xD6, r >= 0xD8 && r <= 0xF6, r >= 0xF8 && r <= 0x2FF:
+		return true
+	case r >= 0x370 && r <= 0x37D, r >= 0x37F && r <= 0x1FFF, r >= 0x200C && r <= 0x200D:
+		return true
+	case r >= 0x2070 && r <= 0x218F, r >= 0x2C00 && r <= 0x2FEF, r >= 0x3001 && r <= 0xD7FF:
+		return true
+	case r >= 0xF900 && r <= 0xFDCF, r >= 0xFDF0 && r <= 0xFFFD, r >= 0x10000 && r <= 0xEFFFF:
+		return true
+	}
+
+	return false
+}
+
+// isXMLNameChar implements the NameChar production of https://www.w3.org/TR/xml/#NT-NameChar
+func isXMLNameChar(r rune) bool {
+	switch {
+	case isXMLNameStartChar(r):
+		return true
+	case r == dash, r == dotSign, r >= '0' && r <= '9', r == 0xB7:
+		return true
+	case r >= 0x300 && r <= 0x36F, r >= 0x203F && r <= 0x2040:
+		return true
+	}
+
+	return false
+}
+
+// isPCENChar implements the PCENChar production of
+// https://html.spec.whatwg.org/multipage/custom-elements.html#valid-custom-element-name
+//
+// nolint: cyclop
+func isPCENChar(r rune) bool {
+	switch {
+	case r == dash, r == dotSign, r == '_', r >= '0' && r <= '9', r >= 'a' && r <= 'z', r == 0xB7:
+		return true
+	case r >= 0xC0 && r <= 0xD6, r >= 0xD8 && r <= 0xF6, r >= 0xF8 && r <= 0x37D:
+		return true
+	case r >= 0x37F && r <= 0x1FFF, r >= 0x200C && r <= 0x200D, r >= 0x203F && r <= 0x2040:
+		return true
+	case r >= 0x2070 && r <= 0x218F, r >= 0x2C00 && r <= 0x2FEF, r >= 0x3001 && r <= 0xD7FF:
+		return true
+	case r >= 0xF900 && r <= 0xFDCF, r >= 0xFDF0 && r <= 0xFFFD, r >= 0x10000 && r <= 0xEFFFF:
+		return true
+	}
+
+	return false
+}
+
+func isASCIILetter(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z'
+}
+
+func isASCIIAlphanumeric(r rune) bool {
+	return isASCIILetter(r) || r >= '0' && r <= '9'
+}
+
+// invalidXMLTagName returns the rune offset of the first character which can't be part of an XML name, or -1.
+func invalidXMLTagName(runes []rune) int {
+	for idx, r := range runes {
+		if idx == 0 && !isXMLNameStartChar(r) || !isXMLNameChar(r) {
+			return idx
+		}
+	}
+
+	return -1
+}
+
+// invalidHTMLTagName returns the rune offset of the first character which can't be part of an HTML tag name, or -1.
+// Anything after a colon is ignored as it's the name of a snippet (e.g. a:link) or a namespaced tag (e.g. svg:rect).
+// Names containing a dash must be valid custom element names, e.g. my-card.
+func invalidHTMLTagName(runes []rune) int {
+	if idx := slices.Index(runes, colon); idx >= 0 {
+		runes = runes[:idx]
+	}
+
+	if len(runes) == 0 {
+		return 0
+	}
+
+	if !isASCIILetter(runes[0]) {
+		return 0
+	}
+
+	if !slices.Contains(runes, dash) {
+		for idx, r := range runes {
+			if !isASCIIAlphanumeric(r) {
+				return idx
+			}
+		}
+
+		return -1
+	}
+
+	if !unicode.IsLower(runes[0]) {
+		return 0
+	}
+
+	for idx, r := range runes {
+		if !isPCENChar(r) {
+			return idx
+		}
+	}
+
+	return -1
+}
+
+// validTagName checks name against the tag name grammar of the lexer's mode. On failure it returns the rune offset
+// of the offending character.
+func (l *Lexer) validTagName(name []rune) (int, error) {
+	if _, ok := documentSnippets[l.mode]; ok && slices.Contains(documentSnippetNames, string(name)) {
+		return 0, nil
+	}
+
+	if l.mode == ModeXML {
+		if idx := invalidXMLTagName(name); idx >= 0 {
+			return idx, expected(ErrInvalidTagName, "XML name")
+		}
+
+		return 0, nil
+	}
+
+	if idx := invalidHTMLTagName(name); idx >= 0 {
+		return idx, expected(ErrInvalidTagName, "HTML tag or custom element name")
+	}
+
+	return 0, nil
+}
@@ -0,0 +1,304 @@
+package expand
+
+import (
+	"regexp"
+	"strings"
+)
+
+// templateLiteral matches the class names and ids which can be written in the shorthand of template languages, e.g.
+// li#item01.item.
+//
+// nolint: gochecknoglobals
+var templateLiteral = regexp.MustCompile(`^-?[_a-zA-Z][_a-zA-Z0-9-]*$`)
+
+// rubySymbol matches the attribute names which can be HAML hash keys without quotes.
+//
+// nolint: gochecknoglobals
+var rubySymbol = regexp.MustCompile(`^[_a-zA-Z][_a-zA-Z0-9]*$`)
+
+// slimAttribute matches text which Slim would take for an attribute after a tag, e.g. a=b.
+//
+// nolint: gochecknoglobals
+var slimAttribute = regexp.MustCompile(`^[^\s=]+\s*=`)
+
+// Interpolations are escaped in text, and in the double-quoted attribute values of the Ruby based languages.
+//
+// nolint: gochecknoglobals
+var (
+	pugInterpolation  = strings.NewReplacer("#{", `\#{`, "!{", `\!{`, "#[", `\#[`)
+	rubyInterpolation = strings.NewReplacer("#{", `\#{`)
+)
+
+// templateAttr is an attribute of an element written in a template language, its value is already escaped.
+type templateAttr struct {
+	name       string
+	value      string
+	boolean    bool
+	expression bool
+}
+
+// templateDialect holds the differences of the template languages expressing nesting by indentation.
+type templateDialect struct {
+	tagPrefix string
+	doctype   string
+	comment   string
+	// pipe starts lines of text, HAML escapes special first characters by a backslash instead.
+	pipe string
+	// special are the first characters which make text after a tag mean something else, e.g. p= expression.
+	special       string
+	interpolation *strings.Replacer
+	// ruby tells if double-quoted attribute values are interpolated.
+	ruby bool
+	// attrLikeText matches text which can't follow the tag on the same line as it looks like an attribute.
+	attrLikeText *regexp.Regexp
+	// attrOpen, attrSeparator and attrClose surround and separate the attributes.
+	attrOpen      string
+	attrSeparator string
+	attrClose     string
+	attr          func(attr templateAttr, quote string) string
+}
+
+// nolint: gochecknoglobals
+var templateDialects = map[Syntax]templateDialect{
+	SyntaxPug: {
+		doctype:       "doctype html",
+		comment:       "//",
+		pipe:          "|",
+		special:       "=!&",
+		interpolation: pugInterpolation,
+		attrOpen:      "(",
+		attrSeparator: ", ",
+		attrClose:     ")",
+		attr: func(attr templateAttr, quote string) string {
+			switch {
+			case attr.boolean:
+				return attr.name
+			case attr.expression:
+				return attr.name + "=" + attr.value
+			}
+
+			return attr.name + "=" + quote + attr.value + quote
+		},
+	},
+	SyntaxHAML: {
+		tagPrefix:     "%",
+		doctype:       "!!! 5",
+		comment:       "/",
+		special:       `%.#-=~&!/:\`,
+		interpolation: rubyInterpolation,
+		ruby:          true,
+		attrOpen:      "{",
+		attrSeparator: ", ",
+		attrClose:     "}",
+		attr: func(attr templateAttr, quote string) string {
+			key := attr.name
+			if !rubySymbol.MatchString(key) {
+				key = `"` + key + `"`
+			}
+
+			switch {
+			case attr.boolean:
+				return key + ": true"
+			case attr.expression:
+				return key + ": " + attr.value
+			}
+
+			return key + ": " + quote + attr.value + quote
+		},
+	},
+	SyntaxSlim: {
+		doctype:       "doctype html",
+		comment:       "/!",
+		pipe:          "|",
+		special:       "=",
+		interpolation: rubyInterpolation,
+		ruby:          true,
+		attrLikeText:  slimAttribute,
+		attrOpen:      " ",
+		attrSeparator: " ",
+		attr: func(attr templateAttr, quote string) string {
+			switch {
+			case attr.boolean:
+				return attr.name + "=true"
+			case attr.expression:
+				return attr.name + "=" + attr.value
+			}
+
+			return attr.name + "=" + quote + attr.value + quote
+		},
+	},
+}
+
+// escapeAttr escapes an attribute value written as a string literal of the language, by backslashes.
+func (d templateDialect) escapeAttr(value, quote string) string {
+	value = escape(value, map[rune]string{'\\': `\\`, rune(quote[0]): `\` + quote, '\n': `\n`})
+
+	if d.ruby && quote == `"` {
+		return rubyInterpolation.Replace(value)
+	}
+
+	return value
+}
+
+// TemplateRenderer writes template languages expressing nesting by indentation, e.g. ul.list with the li.item
+// children indented below it. Text, attributes, tab stops and comments are written like MarkupRenderer does.
+type TemplateRenderer struct {
+	dialect templateDialect
+}
+
+// nolint: gochecknoglobals
+var (
+	PugRenderer  = TemplateRenderer{dialect: templateDialects[SyntaxPug]}
+	HAMLRenderer = TemplateRenderer{dialect: templateDialects[SyntaxHAML]}
+	SlimRenderer = TemplateRenderer{dialect: templateDialects[SyntaxSlim]}
+)
+
+func (r TemplateRenderer) Render(builder *strings.Builder, elemList ElemList, counter *Counter, opts Options) {
+	for _, e := range elemList {
+		r.elem(builder, e, counter, opts, opts.Depth)
+	}
+}
+
+func (r TemplateRenderer) elem(builder *strings.Builder, e *Elem, counter *Counter, opts Options, depth int) {
+	indentation := strings.Repeat(opts.blockIndentation(), depth)
+
+	switch {
+	case e.Raw == doctypeHTML:
+		builder.WriteString(indentation + r.dialect.doctype + "\n")
+
+		return
+
+	case e.Raw != "":
+		builder.WriteString(indentation + e.Raw + "\n")
+
+		return
+
+	case e.Name == "":
+		r.text(builder, e.GetText(opts), indentation)
+
+		return
+	}
+
+	builder.WriteString(indentation)
+	builder.WriteString(r.tag(e, counter, opts))
+
+	text := e.GetText(opts)
+
+	switch {
+	case r.inline(text):
+		builder.WriteString(" " + text)
+		text = ""
+
+	// void and empty XML elements have no content to jump to
+	case text == "" && len(e.Children) == 0 && !e.selfCloses(opts.Mode):
+		if tabStop := opts.tabStop(counter.Get(), ""); tabStop != "" {
+			builder.WriteString(" " + tabStop)
+		}
+	}
+
+	builder.WriteString("\n")
+
+	r.text(builder, text, indentation+opts.blockIndentation())
+
+	for _, child := range e.Children {
+		r.elem(builder, child, counter, opts, depth+1)
+	}
+
+	if comment := e.commentText(opts); comment != "" {
+		builder.WriteString(indentation + r.dialect.comment + " " + comment + "\n")
+	}
+}
+
+// inline tells if text can follow the tag on the same line.
+func (r TemplateRenderer) inline(text string) bool {
+	switch {
+	case text == "" || strings.Contains(text, "\n"):
+		return false
+	case strings.ContainsAny(text[:1], r.dialect.special):
+		return false
+	}
+
+	return r.dialect.attrLikeText == nil || !r.dialect.attrLikeText.MatchString(text)
+}
+
+// tag returns the tag of e with its id, classes and attributes, div is left out if it has an id or classes.
+func (r TemplateRenderer) tag(e *Elem, counter *Counter, opts Options) string {
+	var (
+		builder   strings.Builder
+		shorthand strings.Builder
+		attrs     []templateAttr
+		classes   []string
+	)
+
+	if id := e.GetID(); templateLiteral.MatchString(id) {
+		shorthand.WriteString("#" + id)
+	} else if id != "" {
+		attrs = append(attrs, templateAttr{name: "id", value: opts.escapeAttr(id)})
+	}
+
+	for _, class := range e.Classes {
+		value := class.getValue(e.repetition(class.Level))
+
+		if templateLiteral.MatchString(value) {
+			shorthand.WriteString("." + value)
+		} else {
+			classes = append(classes, value)
+		}
+	}
+
+	if len(classes) > 0 {
+		attrs = append(attrs, templateAttr{name: "class", value: opts.escapeAttr(strings.Join(classes, " "))})
+	}
+
+	for _, attr := range e.Attributes {
+		switch {
+		case attr.IsBoolean() && opts.BooleanAttributes == BooleanAttributeExpanded:
+			attrs = append(attrs, templateAttr{name: attr.Name, value: attr.Name})
+		case attr.IsBoolean():
+			attrs = append(attrs, templateAttr{name: attr.Name, boolean: true})
+		case attr.Expression:
+			attrs = append(attrs, templateAttr{name: attr.Name, value: attr.GetExpression(counter, opts), expression: true})
+		default:
+			attrs = append(attrs, templateAttr{name: attr.Name, value: e.attrValue(attr, counter, opts)})
+		}
+	}
+
+	if e.Name != "div" || shorthand.Len() == 0 {
+		builder.WriteString(r.dialect.tagPrefix + e.Name)
+	}
+
+	builder.WriteString(shorthand.String())
+
+	if len(attrs) > 0 {
+		written := make([]string, 0, len(attrs))
+		for _, attr := range attrs {
+			written = append(written, r.dialect.attr(attr, opts.quote()))
+		}
+
+		builder.WriteString(r.dialect.attrOpen + strings.Join(written, r.dialect.attrSeparator) + r.dialect.attrClose)
+	}
+
+	return builder.String()
+}
+
+// text writes text on lines of its own, e.g. | Hello.
+func (r TemplateRenderer) text(builder *strings.Builder, text, indentation string) {
+	if text == "" {
+		return
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		switch {
+		case r.dialect.pipe != "" && line == "":
+			builder.WriteString(indentation + r.dialect.pipe + "\n")
+		case r.dialect.pipe != "":
+			builder.WriteString(indentation + r.dialect.pipe + " " + line + "\n")
+		case line == "":
+			builder.WriteString("\n")
+		case strings.ContainsAny(line[:1], r.dialect.special):
+			builder.WriteString(indentation + `\` + line + "\n")
+		default:
+			builder.WriteString(indentation + line + "\n")
+		}
+	}
+}
@@ -0,0 +1,290 @@
+package expand
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpand_Pug(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		abbr string
+		opts Options
+		want string
+	}{
+		{
+			name: "ids, classes and nesting",
+			abbr: "ul.list>li#item$$.item*2",
+			opts: Options{Indentation: "  "},
+			want: "ul.list\n  li#item01.item\n  li#item02.item",
+		},
+		{
+			name: "div is implicit with an id or classes",
+			abbr: "div.card>div+#main",
+			opts: Options{Indentation: "  "},
+			want: ".card\n  div\n  #main",
+		},
+		{
+			name: "attributes",
+			abbr: `a[title='say "hi"' data-id=$]*2`,
+			opts: Options{Indentation: "  "},
+			want: "a(title=\"say \\\"hi\\\"\", data-id=\"1\", href=\"#\")\na(title=\"say \\\"hi\\\"\", data-id=\"2\", href=\"#\")",
+		},
+		{
+			name: "boolean attributes",
+			abbr: "input[disabled]",
+			opts: Options{Indentation: "  "},
+			want: `input(disabled, type="text", name="")`,
+		},
+		{
+			name: "classes which can not be written as a shorthand",
+			abbr: `div[class="w-1/2"]+p#a.b`,
+			opts: Options{Indentation: "  "},
+			want: "div(class=\"w-1/2\")\np#a.b",
+		},
+		{
+			name: "text",
+			abbr: "p{Hello }>strong{World}",
+			opts: Options{Indentation: "  "},
+			want: "p Hello \n  strong World",
+		},
+		{
+			name: "document",
+			abbr: "!",
+			opts: Options{Indentation: "  "},
+			want: "doctype html\n" +
+				"html(lang=\"en\")\n" +
+				"  head\n" +
+				"    meta(http-equiv=\"Content-Type\", content=\"text/html;charset=UTF-8\")\n" +
+				"    meta(name=\"viewport\", content=\"width=device-width, user-scalable=no, initial-scale=1.0, maximum-scale=1.0, minimum-scale=1.0\")\n" +
+				"    title Document\n" +
+				"  body",
+		},
+		{
+			name: "comments",
+			abbr: "div#main>p.intro|c",
+			opts: Options{Indentation: "  "},
+			want: "#main\n  p.intro\n  // /.intro\n// /#main",
+		},
+		{
+			name: "depth and indentation",
+			abbr: "ul>li",
+			opts: Options{Indentation: "\t", Depth: 1},
+			want: "ul\n\t\tli", // leading whitespace is trimmed from the output
+		},
+		{
+			name: "default indentation",
+			abbr: "ul>li",
+			opts: Options{Indentation: "", Multiline: false},
+			want: "ul\n    li",
+		},
+		{
+			name: "tab stops",
+			abbr: "a+p",
+			opts: Options{Indentation: "  ", TabStop: TabStopSnippet},
+			want: "a(href=\"${1:#}\") ${2}\np ${3}",
+		},
+		{
+			name: "tab stops of void elements",
+			abbr: "div>input+br",
+			opts: Options{Indentation: "  ", TabStop: TabStopSnippet},
+			want: "div\n  input(type=\"text\", name=\"${1}\")\n  br",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tt.opts.Syntax = SyntaxPug
+
+			got, err := Expand(tt.abbr, tt.opts)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestExpand_HAML(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		abbr string
+		opts Options
+		want string
+	}{
+		{
+			name: "ids, classes and nesting",
+			abbr: "ul.list>li#item$$.item*2",
+			opts: Options{Indentation: "  "},
+			want: "%ul.list\n  %li#item01.item\n  %li#item02.item",
+		},
+		{
+			name: "div is implicit with an id or classes",
+			abbr: "div.card>div+#main",
+			opts: Options{Indentation: "  "},
+			want: ".card\n  %div\n  #main",
+		},
+		{
+			name: "attributes",
+			abbr: `a[title='say "hi" #{x}' data-id=$]`,
+			opts: Options{Indentation: "  "},
+			want: `%a{title: "say \"hi\" \#{x}", "data-id": "1", href: "#"}`,
+		},
+		{
+			name: "single quotes",
+			abbr: `a[title="it's #{x}"]`,
+			opts: Options{Indentation: "  ", Quote: QuoteSingle},
+			want: `%a{title: 'it\'s #{x}', href: '#'}`,
+		},
+		{
+			name: "boolean attributes",
+			abbr: "input[disabled]",
+			opts: Options{Indentation: "  "},
+			want: `%input{disabled: true, type: "text", name: ""}`,
+		},
+		{
+			name: "text",
+			abbr: "p{Hello }>strong{World}",
+			opts: Options{Indentation: "  "},
+			want: "%p Hello \n  %strong World",
+		},
+		{
+			name: "text starting with a special character",
+			abbr: "p{= x}",
+			opts: Options{Indentation: "  "},
+			want: "%p\n  \\= x",
+		},
+		{
+			name: "document",
+			abbr: "!!!+html",
+			opts: Options{Indentation: "  "},
+			want: "!!! 5\n%html",
+		},
+		{
+			name: "comments",
+			abbr: "div#main>p.intro|c",
+			opts: Options{Indentation: "  "},
+			want: "#main\n  %p.intro\n  / /.intro\n/ /#main",
+		},
+		{
+			name: "tab stops",
+			abbr: "a+p",
+			opts: Options{Indentation: "  ", TabStop: TabStopSnippet},
+			want: "%a{href: \"${1:#}\"} ${2}\n%p ${3}",
+		},
+		{
+			name: "tab stops of void elements",
+			abbr: "input+p",
+			opts: Options{Indentation: "  ", TabStop: TabStopSnippet},
+			want: "%input{type: \"text\", name: \"${1}\"}\n%p ${2}",
+		},
+		{
+			name: "tab stops of empty xml elements",
+			abbr: "feed>entry[id=]+entry",
+			opts: Options{Mode: ModeXML, Indentation: "  ", TabStop: TabStopSnippet},
+			want: "%feed\n  %entry{id: \"${1}\"}\n  %entry",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tt.opts.Syntax = SyntaxHAML
+
+			got, err := Expand(tt.abbr, tt.opts)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestExpand_Slim(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		abbr string
+		opts Options
+		want string
+	}{
+		{
+			name: "ids, classes and nesting",
+			abbr: "ul.list>li#item$$.item*2",
+			opts: Options{Indentation: "  "},
+			want: "ul.list\n  li#item01.item\n  li#item02.item",
+		},
+		{
+			name: "attributes and text",
+			abbr: `a[title='say "hi"' data-id=$]{Home}`,
+			opts: Options{Indentation: "  "},
+			want: `a title="say \"hi\"" data-id="1" href="#" Home`,
+		},
+		{
+			name: "boolean attributes",
+			abbr: "input[disabled]",
+			opts: Options{Indentation: "  "},
+			want: `input disabled=true type="text" name=""`,
+		},
+		{
+			name: "text looking like an attribute",
+			abbr: "p{a = b}",
+			opts: Options{Indentation: "  "},
+			want: "p\n  | a = b",
+		},
+		{
+			name: "text looking like an output",
+			abbr: "p{= x}",
+			opts: Options{Indentation: "  "},
+			want: "p\n  | = x",
+		},
+		{
+			name: "document",
+			abbr: "!!!+html",
+			opts: Options{Indentation: "\t"},
+			want: "doctype html\nhtml",
+		},
+		{
+			name: "comments",
+			abbr: "div#main>p.intro|c",
+			opts: Options{Indentation: "  "},
+			want: "#main\n  p.intro\n  /! /.intro\n/! /#main",
+		},
+		{
+			name: "tab stops of void elements",
+			abbr: "input+br+p",
+			opts: Options{Indentation: "  ", TabStop: TabStopSnippet},
+			want: "input type=\"text\" name=\"${1}\"\nbr\np ${2}",
+		},
+		{
+			name: "depth",
+			abbr: "ul>li",
+			opts: Options{Indentation: "  ", Depth: 2},
+			want: "ul\n      li",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tt.opts.Syntax = SyntaxSlim
+
+			got, err := Expand(tt.abbr, tt.opts)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
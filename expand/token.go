@@ -1,10 +1,12 @@
-package main
+package expand
 
 type TokenType string
 
 const (
 	ID    TokenType = "ID"
 	Class TokenType = "Class"
+	// Content marks where wrapped content goes in attribute values and text, see Wrap.
+	Content TokenType = "Content"
 )
 
 type Token interface {
@@ -16,14 +18,20 @@ type Token interface {
 }
 
 type TagToken struct {
-	Name       string
-	Repeat     int
-	Classes    AttrValues
-	ID         *AttrValue
-	Attributes AttrList
-	Text       *Text
-	Parent     Token
-	Children   []Token
+	Name   string
+	Repeat int
+	// ImplicitRepeat is set for elements repeated without a count, e.g. li*
+	ImplicitRepeat bool
+	Classes        AttrValues
+	ID             *AttrValue
+	Attributes     AttrList
+	Text           *Text
+	Parent         Token
+	Children       []Token
+	// expanded is set once a snippet gave the tag its children, see Snippet.Children
+	expanded bool
+	// snippets are the user snippets whose children the tag is part of, they are not applied to it to stop on cycles
+	snippets []string
 }
 
 func NewTagToken(name string, repeat int) *TagToken {
@@ -33,6 +41,14 @@ func NewTagToken(name string, repeat int) *TagToken {
 	}
 }
 
+// SetRepeat sets the repeat count, 0 marks an implicit repeat.
+func (t *TagToken) SetRepeat(repeat int) *TagToken {
+	t.Repeat = max(repeat, 1)
+	t.ImplicitRepeat = repeat == 0
+
+	return t
+}
+
 func (t *TagToken) SetName(name string) *TagToken {
 	t.Name = name
 
@@ -51,6 +67,17 @@ func (t *TagToken) AddClass(class *AttrValue) *TagToken {
 	return t
 }
 
+// hasClass tells if the tag has a class of value without numbering.
+func (t *TagToken) hasClass(value string) bool {
+	for _, class := range t.Classes {
+		if class.Value == value && class.Numbering == "" {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (t *TagToken) AddAttribute(attribute *Attr) *TagToken {
 	t.Attributes = append(t.Attributes, attribute)
 
@@ -102,6 +129,17 @@ func (t *TagToken) SetParent(parent Token) Token {
 }
 
 // nolint: ireturn
+// inSnippet tells if the tag is part of the children of the snippet called name.
+func (t *TagToken) inSnippet(name string) bool {
+	for _, snippet := range t.snippets {
+		if snippet == name {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (t *TagToken) AddChildren(children ...Token) Token {
 	for _, child := range children {
 		child.SetParent(t)
@@ -120,10 +158,50 @@ func (t *TagToken) GetRepeat() int {
 	return t.Repeat
 }
 
+// RawToken is markup written to the output as it is, e.g. a doctype. It has no children, the snippets creating it
+// move the children given in the abbreviation next to it.
+type RawToken struct {
+	Value  string
+	Parent Token
+}
+
+func NewRawToken(value string) *RawToken {
+	return &RawToken{
+		Value: value,
+	}
+}
+
+func (r *RawToken) GetRepeat() int {
+	return 1
+}
+
+// nolint: ireturn
+func (r *RawToken) GetParent() Token {
+	return r.Parent
+}
+
+// nolint: ireturn
+func (r *RawToken) SetParent(parent Token) Token {
+	r.Parent = parent
+
+	return r
+}
+
+// nolint: ireturn
+func (r *RawToken) AddChildren(...Token) Token {
+	return r
+}
+
+func (r *RawToken) GetChildren() []Token {
+	return nil
+}
+
 type GroupToken struct {
 	Children []Token
 	Parent   Token
 	Repeat   int
+	// ImplicitRepeat is set for groups repeated without a count, e.g. (dt+dd)*
+	ImplicitRepeat bool
 }
 
 func NewGroupToken(repeat int, tokens ...Token) *GroupToken {
@@ -137,6 +215,14 @@ func NewGroupToken(repeat int, tokens ...Token) *GroupToken {
 	return token
 }
 
+// SetRepeat sets the repeat count, 0 marks an implicit repeat.
+func (g *GroupToken) SetRepeat(repeat int) *GroupToken {
+	g.Repeat = max(repeat, 1)
+	g.ImplicitRepeat = repeat == 0
+
+	return g
+}
+
 func (g *GroupToken) GetRepeat() int {
 	return g.Repeat
 }
@@ -0,0 +1,191 @@
+package expand
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// wrapContent is the content wrapped by an abbreviation.
+type wrapContent struct {
+	// lines are the trimmed, non-empty lines of the content, one for each copy of an implicitly repeated element.
+	lines []string
+	// text is the whole content, with the common indentation removed.
+	text string
+}
+
+func newWrapContent(content string) *wrapContent {
+	rawLines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+
+	var lines []string
+
+	for _, line := range rawLines {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	return &wrapContent{lines: lines, text: dedent(rawLines)}
+}
+
+// dedent drops the leading and trailing blank lines and removes the indentation shared by the remaining ones.
+func dedent(lines []string) string {
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	prefix := -1
+
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		indentation := len(line) - len(strings.TrimLeft(line, " \t"))
+		if prefix < 0 || indentation < prefix {
+			prefix = indentation
+		}
+	}
+
+	dedented := make([]string, 0, len(lines))
+
+	for _, line := range lines {
+		line = strings.TrimRight(line, " \t")
+		if len(line) >= prefix {
+			line = line[prefix:]
+		}
+
+		dedented = append(dedented, line)
+	}
+
+	return strings.Join(dedented, "\n")
+}
+
+// get returns the line belonging to the given copy of an implicitly repeated element, or the whole content outside
+// of implicit repeats.
+func (w *wrapContent) get(wrapIndex int) string {
+	if w == nil {
+		return ""
+	}
+
+	if wrapIndex > 0 && wrapIndex <= len(w.lines) {
+		return w.lines[wrapIndex-1]
+	}
+
+	return w.text
+}
+
+// Wrap expands abbr around content, e.g. "ul>li*" wraps each line of content in a list item. Content goes where the
+// abbreviation has a $# placeholder, or into the deepest last element if it has none. Elements repeated without a
+// count (*) are repeated once for each non-empty line of content.
+func Wrap(abbr, content string, opts Options) (string, error) {
+	if err := opts.Validate(); err != nil {
+		return "", errors.Wrap(err, ErrInvalidOptionsMsg)
+	}
+
+	opts = opts.withDefaults()
+	opts.wrap = newWrapContent(content)
+
+	got, _, err := render(abbr, opts)
+
+	return got, err
+}
+
+// repeatImplicit sets the number of copies of implicitly repeated tokens.
+func repeatImplicit(tokens []Token, count int) {
+	for _, token := range tokens {
+		switch value := token.(type) {
+		case *TagToken:
+			if value.ImplicitRepeat {
+				value.Repeat = max(count, 1)
+			}
+		case *GroupToken:
+			if value.ImplicitRepeat {
+				value.Repeat = max(count, 1)
+			}
+		}
+
+		repeatImplicit(token.GetChildren(), count)
+	}
+}
+
+// hasContent tells whether any of the elements has a $# placeholder.
+func (el ElemList) hasContent() bool {
+	for _, e := range el {
+		if e.Text != nil && e.Text.parts.hasContent() {
+			return true
+		}
+
+		for _, attr := range e.Attributes {
+			if attr.Parts.hasContent() {
+				return true
+			}
+		}
+
+		if e.Children.hasContent() {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (values AttrValues) hasContent() bool {
+	for _, value := range values {
+		if value.Type == Content {
+			return true
+		}
+	}
+
+	return false
+}
+
+// insertContent puts the wrapped content into the deepest last element of each copy made by an implicit repeat, or
+// into the deepest last element of the list if there was no implicit repeat.
+func (el ElemList) insertContent() {
+	targets := map[int]*Elem{}
+	el.collectCopies(0, targets)
+
+	if len(targets) == 0 {
+		if last := el.deepestLast(); last != nil {
+			last.Text = last.Text.withContent()
+		}
+
+		return
+	}
+
+	for _, target := range targets {
+		last := ElemList{target}.deepestLast()
+		last.Text = last.Text.withContent()
+	}
+}
+
+// collectCopies maps each copy made by the outermost implicit repeat to its last top level element.
+func (el ElemList) collectCopies(parentIndex int, targets map[int]*Elem) {
+	for _, e := range el {
+		if parentIndex == 0 && e.WrapIndex != 0 {
+			targets[e.WrapIndex] = e
+
+			continue
+		}
+
+		e.Children.collectCopies(e.WrapIndex, targets)
+	}
+}
+
+func (el ElemList) deepestLast() *Elem {
+	if len(el) == 0 {
+		return nil
+	}
+
+	last := el[len(el)-1]
+	if deeper := last.Children.deepestLast(); deeper != nil {
+		return deeper
+	}
+
+	return last
+}
@@ -0,0 +1,130 @@
+package expand
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrap(t *testing.T) {
+	t.Parallel()
+
+	const content = "  Home\n\n  About <us>\n"
+
+	tests := []struct {
+		name    string
+		abbr    string
+		content string
+		opts    Options
+		want    string
+	}{
+		{
+			name:    "deepest last element",
+			abbr:    "div>p{x}+span.note",
+			content: content,
+			opts:    Options{Mode: ModeHTML},
+			want:    "<div><p>x</p><span class=\"note\">Home\n\nAbout <us></span></div>",
+		},
+		{
+			name:    "implicit repeat",
+			abbr:    "ul>li.item$*",
+			content: content,
+			opts:    Options{Mode: ModeHTML},
+			want:    `<ul><li class="item1">Home</li><li class="item2">About <us></li></ul>`,
+		},
+		{
+			name:    "implicit repeat of deeper elements",
+			abbr:    "nav>ul>li*>a",
+			content: content,
+			opts:    Options{Mode: ModeHTML},
+			want:    `<nav><ul><li><a href="#">Home</a></li><li><a href="#">About <us></a></li></ul></nav>`,
+		},
+		{
+			name:    "implicit repeat of groups",
+			abbr:    "dl>(dt+dd)*",
+			content: content,
+			opts:    Options{Mode: ModeHTML},
+			want:    `<dl><dt></dt><dd>Home</dd><dt></dt><dd>About <us></dd></dl>`,
+		},
+		{
+			name:    "placeholder in attribute and text",
+			abbr:    "a[title=$#]*{Go to $#}",
+			content: content,
+			opts:    Options{Mode: ModeHTML},
+			want:    `<a title="Home" href="#">Go to Home</a><a title="About &lt;us>" href="#">Go to About <us></a>`,
+		},
+		{
+			name:    "escaped placeholder",
+			abbr:    `p{\$#}`,
+			content: content,
+			opts:    Options{Mode: ModeHTML},
+			want:    "<p>$#Home\n\nAbout <us></p>",
+		},
+		{
+			name:    "empty content",
+			abbr:    "ul>li*",
+			content: "",
+			opts:    Options{Mode: ModeHTML},
+			want:    `<ul><li></li></ul>`,
+		},
+		{
+			name:    "multiline content is indented",
+			abbr:    "div>pre",
+			content: "  func main() {\n    run()\n  }\n",
+			opts:    Options{Mode: ModeHTML, Indentation: "  ", Multiline: true},
+			want:    "<div>\n  <pre>\n    func main() {\n      run()\n    }\n  </pre>\n</div>",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Wrap(tt.abbr, tt.content, tt.opts)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestExpand_ImplicitRepeat(t *testing.T) {
+	t.Parallel()
+
+	got, err := Expand("ul>li.item$*", Options{Mode: ModeHTML})
+	require.NoError(t, err)
+
+	assert.Equal(t, `<ul><li class="item1"></li></ul>`, got)
+}
+
+func Test_dedent(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		lines []string
+		want  string
+	}{
+		{
+			name:  "empty",
+			lines: []string{""},
+			want:  "",
+		},
+		{
+			name:  "common indentation and blank edges",
+			lines: []string{"", "    a", "      b ", "", "    c", "  "},
+			want:  "a\n  b\n\nc",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, dedent(tt.lines))
+		})
+	}
+}
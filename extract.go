@@ -0,0 +1,60 @@
+package main
+
+import (
+	"github.com/peteraba/xemmet/expand"
+)
+
+func cursorOrEnd(line string, column int) int {
+	if column < 0 {
+		return len([]rune(line))
+	}
+
+	return column
+}
+
+// extractText replaces the abbreviation before column in line with its expansion.
+func extractText(line string, column int, opts expand.Options) (string, []*expand.SyntaxError, error) {
+	cursor := cursorOrEnd(line, column)
+
+	abbr, start, err := expand.ExtractAbbreviation(line, cursor)
+	if err != nil {
+		return "", nil, err
+	}
+
+	got, warnings, err := expandText(abbr, opts)
+	if err != nil {
+		return "", nil, shiftSyntaxError(err, line, start)
+	}
+
+	runes := []rune(line)
+
+	return string(runes[:start]) + got + string(runes[cursor:]), shiftWarnings(warnings, line, start), nil
+}
+
+// extractJSON expands the abbreviation before column in line. Positions of errors are relative to the line.
+func extractJSON(line string, column int, opts expand.Options) jsonResult {
+	cursor := cursorOrEnd(line, column)
+
+	abbr, start, err := expand.ExtractAbbreviation(line, cursor)
+	if err != nil {
+		return jsonResult{
+			TabStops: []jsonTabStop{},
+			Error: &jsonError{
+				Message:  err.Error(),
+				Position: start,
+			},
+		}
+	}
+
+	got, err := expand.ExpandWithTabStops(abbr, opts)
+	got.Warnings = shiftWarnings(got.Warnings, line, start)
+
+	result := newJSONResult(got, shiftSyntaxError(err, line, start))
+
+	result.Range = &jsonRange{
+		Start: start,
+		End:   cursor,
+	}
+
+	return result
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/peteraba/xemmet/expand"
+)
+
+func TestExtractText(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		line   string
+		column int
+		want   string
+	}{
+		{
+			name:   "end of line",
+			line:   `<div class="x">ul>li*2`,
+			column: -1,
+			want:   `<div class="x"><ul><li></li><li></li></ul>`,
+		},
+		{
+			name:   "cursor column",
+			line:   `foo p.bar baz`,
+			column: 9,
+			want:   `foo <p class="bar"></p> baz`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, _, err := extractText(tt.line, tt.column, expand.Options{})
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("syntax error is relative to the line", func(t *testing.T) {
+		t.Parallel()
+
+		expandFn, err := newExpander(expanderConfig{format: FormatText, extract: true, column: -1})
+		require.NoError(t, err)
+
+		_, err = expandFn("<p>ul>li.")
+		require.ErrorIs(t, err, expand.ErrInputTooShort)
+
+		assert.Equal(t, "error tokenizing string: syntax error at line 1, column 10: input too short, expected class name, found end of input\n<p>ul>li.\n         ^", err.Error())
+	})
+
+	t.Run("no abbreviation", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, err := extractText("foo ", -1, expand.Options{})
+		require.ErrorIs(t, err, expand.ErrAbbreviationNotFound)
+	})
+}
+
+func TestExtractJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		line   string
+		column int
+		want   string
+	}{
+		{
+			name:   "range",
+			line:   "árvíz p tűrő",
+			column: 7,
+			want:   `{"output":"<p></p>","tabStops":[{"index":1,"offset":3,"length":0,"placeholder":""}],"cursor":3,"range":{"start":6,"end":7}}`,
+		},
+		{
+			name:   "error position is relative to the line",
+			line:   "foo p#",
+			column: -1,
+			want:   `{"output":"","tabStops":[],"cursor":0,"range":{"start":4,"end":6},"error":{"message":"error tokenizing string: syntax error at line 1, column 7: input too short, expected id, found end of input","position":6}}`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := encodeJSON(extractJSON(tt.line, tt.column, expand.Options{}))
+			require.NoError(t, err)
+
+			assert.JSONEq(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/peteraba/xemmet/expand"
+)
+
+type Format string
+
+const (
+	FormatText Format = "text"
+	FormatJSON Format = "json"
+)
+
+var ErrUnknownFormat = errors.New("unknown format")
+
+// expander expands a single input, usually an abbreviation, into its final textual representation.
+type expander func(abbr string) (string, error)
+
+// nolint: tagliatelle
+type jsonTabStop struct {
+	Index       int    `json:"index"`
+	Offset      int    `json:"offset"`
+	Length      int    `json:"length"`
+	Placeholder string `json:"placeholder"`
+}
+
+type jsonRange struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+type jsonError struct {
+	Message  string `json:"message"`
+	Position int    `json:"position"`
+}
+
+// nolint: tagliatelle
+type jsonResult struct {
+	Output   string        `json:"output"`
+	TabStops []jsonTabStop `json:"tabStops"`
+	Cursor   int           `json:"cursor"`
+	Range    *jsonRange    `json:"range,omitempty"`
+	Warnings []jsonError   `json:"warnings,omitempty"`
+	Error    *jsonError    `json:"error,omitempty"`
+}
+
+type expanderConfig struct {
+	format Format
+	opts   expand.Options
+	// extract makes the expander receive full lines and only expand the abbreviation found before column (end of
+	// line if negative)
+	extract bool
+	column  int
+	// warnings is where recovered issues are reported in text format
+	warnings io.Writer
+}
+
+func newExpander(cfg expanderConfig) (expander, error) {
+	switch cfg.format {
+	case FormatText, "":
+		if cfg.extract {
+			return func(line string) (string, error) {
+				got, warnings, err := extractText(line, cfg.column, cfg.opts)
+				cfg.warn(warnings, line)
+
+				return got, withCaret(err, line)
+			}, nil
+		}
+
+		return func(abbr string) (string, error) {
+			got, warnings, err := expandText(abbr, cfg.opts)
+			cfg.warn(warnings, abbr)
+
+			return got, withCaret(err, abbr)
+		}, nil
+
+	case FormatJSON:
+		if cfg.extract {
+			return func(line string) (string, error) {
+				return encodeJSON(extractJSON(line, cfg.column, cfg.opts))
+			}, nil
+		}
+
+		return func(abbr string) (string, error) {
+			return encodeJSON(expandJSON(abbr, cfg.opts))
+		}, nil
+	}
+
+	return nil, errors.Wrapf(ErrUnknownFormat, "format: %q", cfg.format)
+}
+
+func expandText(abbr string, opts expand.Options) (string, []*expand.SyntaxError, error) {
+	if opts.Recover {
+		return expand.ExpandRecover(abbr, opts)
+	}
+
+	got, err := expand.Expand(abbr, opts)
+
+	return got, nil, err
+}
+
+func (cfg expanderConfig) warn(warnings []*expand.SyntaxError, input string) {
+	if cfg.warnings == nil {
+		return
+	}
+
+	for _, warning := range warnings {
+		fmt.Fprintf(cfg.warnings, "warning: %s\n%s\n", warning, warning.Caret(input))
+	}
+}
+
+// expandJSON reports expansion errors as part of the JSON document.
+func expandJSON(abbr string, opts expand.Options) jsonResult {
+	return newJSONResult(expand.ExpandWithTabStops(abbr, opts))
+}
+
+func newJSONResult(got expand.Result, err error) jsonResult {
+	result := jsonResult{
+		TabStops: []jsonTabStop{},
+	}
+
+	if err != nil {
+		result.Error = &jsonError{
+			Message:  err.Error(),
+			Position: -1,
+		}
+
+		var syntaxErr *expand.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			result.Error.Position = syntaxErr.Offset
+		}
+	}
+
+	result.Output = got.Output
+	result.Cursor = got.Cursor
+
+	for _, warning := range got.Warnings {
+		result.Warnings = append(result.Warnings, jsonError{
+			Message:  warning.Error(),
+			Position: warning.Offset,
+		})
+	}
+
+	for _, tabStop := range got.TabStops {
+		result.TabStops = append(result.TabStops, jsonTabStop(tabStop))
+	}
+
+	return result
+}
+
+func encodeJSON(result jsonResult) (string, error) {
+	builder := &strings.Builder{}
+
+	encoder := json.NewEncoder(builder)
+	encoder.SetEscapeHTML(false)
+
+	if err := encoder.Encode(result); err != nil {
+		return "", errors.Wrap(err, "failed to encode result")
+	}
+
+	return strings.TrimSuffix(builder.String(), "\n"), nil
+}
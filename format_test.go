@@ -0,0 +1,71 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/peteraba/xemmet/expand"
+)
+
+func TestExpandJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		abbr string
+		want string
+	}{
+		{
+			name: "tab stops",
+			abbr: "a:link",
+			want: `{"output":"<a href=\"https://\"></a>","tabStops":[{"index":1,"offset":9,"length":8,"placeholder":"https://"},{"index":2,"offset":19,"length":0,"placeholder":""}],"cursor":9}`,
+		},
+		{
+			name: "error",
+			abbr: "p#",
+			want: `{"output":"","tabStops":[],"cursor":0,"error":{"message":"error tokenizing string: syntax error at line 1, column 3: input too short, expected id, found end of input","position":2}}`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := encodeJSON(expandJSON(tt.abbr, expand.Options{}))
+			require.NoError(t, err)
+
+			assert.JSONEq(t, tt.want, got)
+		})
+	}
+}
+
+func TestNewExpander(t *testing.T) {
+	t.Parallel()
+
+	_, err := newExpander(expanderConfig{format: "yaml", column: -1})
+	require.ErrorIs(t, err, ErrUnknownFormat)
+}
+
+func TestNewExpanderRecover(t *testing.T) {
+	t.Parallel()
+
+	var warnings strings.Builder
+
+	expandFn, err := newExpander(expanderConfig{
+		format:   FormatText,
+		opts:     expand.Options{Recover: true},
+		column:   -1,
+		warnings: &warnings,
+	})
+	require.NoError(t, err)
+
+	got, err := expandFn("ul>li{foo")
+	require.NoError(t, err)
+
+	assert.Equal(t, "<ul><li>foo</li></ul>", got)
+	assert.Equal(t, "warning: syntax error at line 1, column 10: directive closing missing, expected '}', found end of input\nul>li{foo\n         ^\n", warnings.String())
+}
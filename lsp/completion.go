@@ -0,0 +1,160 @@
+package lsp
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/peteraba/xemmet/expand"
+)
+
+// languageModes lists the supported language IDs, an empty mode means the mode the server was started with.
+//
+// nolint: gochecknoglobals
+var languageModes = map[string]expand.Mode{
+	"html":            "",
+	"templ":           "",
+	"gotmpl":          "",
+	"gohtml":          "",
+	"gohtmltmpl":      "",
+	"go-template":     "",
+	"tmpl":            "",
+	"xml":             expand.ModeXML,
+	"xsl":             expand.ModeXML,
+	"svg":             expand.ModeXML,
+	"javascriptreact": expand.ModeJSX,
+	"typescriptreact": expand.ModeJSX,
+}
+
+func (s *Server) complete(params completionParams) completionList {
+	list := completionList{
+		IsIncomplete: true,
+		Items:        []completionItem{},
+	}
+
+	doc := s.getDocument(params.TextDocument.URI)
+
+	mode, ok := languageModes[doc.languageID]
+	if !ok {
+		return list
+	}
+
+	opts := s.opts
+	if mode != "" {
+		opts.Mode = mode
+	}
+
+	line, ok := lineAt(doc.text, params.Position.Line)
+	if !ok {
+		return list
+	}
+
+	runes := []rune(line)
+	cursor := runeOffset(runes, params.Position.Character)
+
+	abbr, start, err := expand.ExtractAbbreviation(line, cursor)
+	if err != nil {
+		return list
+	}
+
+	result, err := expand.ExpandWithTabStops(abbr, opts)
+	if err != nil {
+		return list
+	}
+
+	list.Items = append(list.Items, completionItem{
+		Label:            abbr,
+		Kind:             completionItemKind,
+		Detail:           serverName,
+		Documentation:    result.Output,
+		FilterText:       abbr,
+		InsertTextFormat: insertTextFormat,
+		TextEdit: textEdit{
+			Range: textRange{
+				Start: position{Line: params.Position.Line, Character: utf16Length(runes[:start])},
+				End:   params.Position,
+			},
+			NewText: snippet(result),
+		},
+	})
+
+	return list
+}
+
+func lineAt(text string, line int) (string, bool) {
+	lines := strings.Split(text, "\n")
+	if line < 0 || line >= len(lines) {
+		return "", false
+	}
+
+	return strings.TrimSuffix(lines[line], "\r"), true
+}
+
+// runeOffset converts an LSP character offset, counted in UTF-16 code units, into a rune offset.
+func runeOffset(runes []rune, character int) int {
+	units := 0
+
+	for idx, r := range runes {
+		if units >= character {
+			return idx
+		}
+
+		units += runeUTF16Length(r)
+	}
+
+	return len(runes)
+}
+
+func runeUTF16Length(r rune) int {
+	// runes outside the Basic Multilingual Plane are encoded as surrogate pairs
+	if r < 0x10000 { // nolint: gomnd
+		return 1
+	}
+
+	return 2 // nolint: gomnd
+}
+
+func utf16Length(runes []rune) int {
+	units := 0
+
+	for _, r := range runes {
+		units += runeUTF16Length(r)
+	}
+
+	return units
+}
+
+// snippet turns an expansion into LSP snippet syntax, escaping characters with special meaning.
+func snippet(result expand.Result) string {
+	var (
+		builder strings.Builder
+		runes   = []rune(result.Output)
+		pos     = 0
+	)
+
+	for _, tabStop := range result.TabStops {
+		builder.WriteString(escapeSnippet(string(runes[pos:tabStop.Offset])))
+
+		builder.WriteString("${")
+		builder.WriteString(strconv.Itoa(tabStop.Index))
+
+		if tabStop.Placeholder != "" {
+			builder.WriteString(":")
+			builder.WriteString(escapeSnippet(tabStop.Placeholder))
+		}
+
+		builder.WriteString("}")
+
+		pos = tabStop.Offset + tabStop.Length
+	}
+
+	builder.WriteString(escapeSnippet(string(runes[pos:])))
+
+	return builder.String()
+}
+
+// nolint: gochecknoglobals
+var snippetEscaper = strings.NewReplacer(`\`, `\\`, `$`, `\$`, `}`, `\}`)
+
+func escapeSnippet(text string) string {
+	return snippetEscaper.Replace(text)
+}
@@ -0,0 +1,133 @@
+package lsp
+
+import (
+	"encoding/json"
+)
+
+// JSON-RPC and LSP error codes.
+const (
+	codeParseError           = -32700
+	codeMethodNotFound       = -32601
+	codeInvalidParams        = -32602
+	codeServerNotInitialized = -32002
+	codeInvalidRequest       = -32600
+)
+
+const (
+	textDocumentSyncFull = 1
+	completionItemKind   = 15 // Snippet
+	insertTextFormat     = 2  // Snippet
+)
+
+type request struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id,omitempty"`
+	Method  string           `json:"method"`
+	Params  json.RawMessage  `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id"`
+	Result  interface{}      `json:"result"`
+}
+
+type errorResponse struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id"`
+	Error   responseError    `json:"error"`
+}
+
+type responseError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// nolint: tagliatelle
+type initializeResult struct {
+	Capabilities serverCapabilities `json:"capabilities"`
+	ServerInfo   serverInfo         `json:"serverInfo"`
+}
+
+type serverInfo struct {
+	Name string `json:"name"`
+}
+
+// nolint: tagliatelle
+type serverCapabilities struct {
+	TextDocumentSync   int               `json:"textDocumentSync"`
+	CompletionProvider completionOptions `json:"completionProvider"`
+}
+
+// nolint: tagliatelle
+type completionOptions struct {
+	TriggerCharacters []string `json:"triggerCharacters"`
+}
+
+type textDocumentItem struct {
+	URI        string `json:"uri"`
+	LanguageID string `json:"languageId"` // nolint: tagliatelle
+	Text       string `json:"text"`
+}
+
+type textDocumentIdentifier struct {
+	URI string `json:"uri"`
+}
+
+// nolint: tagliatelle
+type didOpenParams struct {
+	TextDocument textDocumentItem `json:"textDocument"`
+}
+
+type contentChange struct {
+	Text string `json:"text"`
+}
+
+// nolint: tagliatelle
+type didChangeParams struct {
+	TextDocument   textDocumentIdentifier `json:"textDocument"`
+	ContentChanges []contentChange        `json:"contentChanges"`
+}
+
+// nolint: tagliatelle
+type didCloseParams struct {
+	TextDocument textDocumentIdentifier `json:"textDocument"`
+}
+
+type position struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+type textRange struct {
+	Start position `json:"start"`
+	End   position `json:"end"`
+}
+
+// nolint: tagliatelle
+type completionParams struct {
+	TextDocument textDocumentIdentifier `json:"textDocument"`
+	Position     position               `json:"position"`
+}
+
+// nolint: tagliatelle
+type completionList struct {
+	IsIncomplete bool             `json:"isIncomplete"`
+	Items        []completionItem `json:"items"`
+}
+
+type textEdit struct {
+	Range   textRange `json:"range"`
+	NewText string    `json:"newText"`
+}
+
+// nolint: tagliatelle
+type completionItem struct {
+	Label            string   `json:"label"`
+	Kind             int      `json:"kind"`
+	Detail           string   `json:"detail"`
+	Documentation    string   `json:"documentation"`
+	FilterText       string   `json:"filterText"`
+	InsertTextFormat int      `json:"insertTextFormat"`
+	TextEdit         textEdit `json:"textEdit"`
+}
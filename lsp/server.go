@@ -0,0 +1,194 @@
+// Package lsp implements a minimal Language Server Protocol server offering abbreviation expansions as completions.
+package lsp
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/peteraba/xemmet/expand"
+)
+
+var ErrExitWithoutShutdown = errors.New("exit received without shutdown")
+
+const serverName = "xemmet"
+
+// nolint: gochecknoglobals
+var triggerCharacters = []string{">", "+", "^", "*", ".", "#", "]", "}", ")"}
+
+type document struct {
+	languageID string
+	text       string
+}
+
+type Server struct {
+	transport   *transport
+	opts        expand.Options
+	documents   map[string]document
+	lock        *sync.Mutex
+	initialized bool
+	shutdown    bool
+}
+
+func NewServer(r io.Reader, w io.Writer, opts expand.Options) *Server {
+	return &Server{
+		transport: newTransport(r, w),
+		opts:      opts,
+		documents: map[string]document{},
+		lock:      &sync.Mutex{},
+	}
+}
+
+// Run serves requests until the client sends an exit notification or closes the input.
+func (s *Server) Run() error {
+	for {
+		body, err := s.transport.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		var req request
+
+		if err := json.Unmarshal(body, &req); err != nil {
+			if err := s.replyError(nil, codeParseError, err.Error()); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if req.Method == "exit" {
+			if !s.shutdown {
+				return ErrExitWithoutShutdown
+			}
+
+			return nil
+		}
+
+		if err := s.handle(req); err != nil {
+			return err
+		}
+	}
+}
+
+// nolint: cyclop
+func (s *Server) handle(req request) error {
+	if !s.initialized && req.Method != "initialize" {
+		if req.ID == nil {
+			return nil
+		}
+
+		return s.replyError(req.ID, codeServerNotInitialized, "server not initialized")
+	}
+
+	if s.shutdown {
+		if req.ID == nil {
+			return nil
+		}
+
+		return s.replyError(req.ID, codeInvalidRequest, "server is shutting down")
+	}
+
+	switch req.Method {
+	case "initialize":
+		s.initialized = true
+
+		return s.reply(req.ID, initializeResult{
+			Capabilities: serverCapabilities{
+				TextDocumentSync: textDocumentSyncFull,
+				CompletionProvider: completionOptions{
+					TriggerCharacters: triggerCharacters,
+				},
+			},
+			ServerInfo: serverInfo{Name: serverName},
+		})
+
+	case "shutdown":
+		s.shutdown = true
+
+		return s.reply(req.ID, nil)
+
+	case "textDocument/didOpen":
+		var params didOpenParams
+		if err := json.Unmarshal(req.Params, &params); err == nil {
+			s.setDocument(params.TextDocument.URI, params.TextDocument.LanguageID, params.TextDocument.Text)
+		}
+
+	case "textDocument/didChange":
+		var params didChangeParams
+		if err := json.Unmarshal(req.Params, &params); err == nil && len(params.ContentChanges) > 0 {
+			doc := s.getDocument(params.TextDocument.URI)
+			s.setDocument(params.TextDocument.URI, doc.languageID, params.ContentChanges[len(params.ContentChanges)-1].Text)
+		}
+
+	case "textDocument/didClose":
+		var params didCloseParams
+		if err := json.Unmarshal(req.Params, &params); err == nil {
+			s.deleteDocument(params.TextDocument.URI)
+		}
+
+	case "textDocument/completion":
+		var params completionParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return s.replyError(req.ID, codeInvalidParams, err.Error())
+		}
+
+		return s.reply(req.ID, s.complete(params))
+
+	default:
+		if req.ID != nil {
+			return s.replyError(req.ID, codeMethodNotFound, "method not found: "+req.Method)
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) getDocument(uri string) document {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.documents[uri]
+}
+
+func (s *Server) setDocument(uri, languageID, text string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.documents[uri] = document{
+		languageID: languageID,
+		text:       text,
+	}
+}
+
+func (s *Server) deleteDocument(uri string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	delete(s.documents, uri)
+}
+
+func (s *Server) reply(id *json.RawMessage, result interface{}) error {
+	return s.transport.Write(response{
+		JSONRPC: "2.0",
+		ID:      id,
+		Result:  result,
+	})
+}
+
+func (s *Server) replyError(id *json.RawMessage, code int, message string) error {
+	return s.transport.Write(errorResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Error: responseError{
+			Code:    code,
+			Message: message,
+		},
+	})
+}
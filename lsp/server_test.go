@@ -0,0 +1,219 @@
+package lsp
+
+import (
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/peteraba/xemmet/expand"
+)
+
+// testClient is a minimal JSON-RPC client talking to a Server through pipes.
+type testClient struct {
+	t         *testing.T
+	transport *transport
+	nextID    int
+	done      chan error
+}
+
+func newTestClient(t *testing.T, opts expand.Options) *testClient {
+	t.Helper()
+
+	clientReader, serverWriter := io.Pipe()
+	serverReader, clientWriter := io.Pipe()
+
+	client := &testClient{
+		t:         t,
+		transport: newTransport(clientReader, clientWriter),
+		done:      make(chan error, 1),
+	}
+
+	go func() {
+		client.done <- NewServer(serverReader, serverWriter, opts).Run()
+
+		_ = serverWriter.Close()
+	}()
+
+	t.Cleanup(func() {
+		_ = clientWriter.Close()
+	})
+
+	return client
+}
+
+func (c *testClient) notify(method string, params interface{}) {
+	c.t.Helper()
+
+	require.NoError(c.t, c.transport.Write(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  method,
+		"params":  params,
+	}))
+}
+
+func (c *testClient) call(method string, params interface{}) map[string]interface{} {
+	c.t.Helper()
+
+	c.nextID++
+
+	require.NoError(c.t, c.transport.Write(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      c.nextID,
+		"method":  method,
+		"params":  params,
+	}))
+
+	body, err := c.transport.Read()
+	require.NoError(c.t, err)
+
+	var got map[string]interface{}
+
+	require.NoError(c.t, json.Unmarshal(body, &got))
+	assert.InDelta(c.t, float64(c.nextID), got["id"], 0)
+
+	return got
+}
+
+func (c *testClient) completion(uri string, line, character int) []interface{} {
+	c.t.Helper()
+
+	got := c.call("textDocument/completion", map[string]interface{}{
+		"textDocument": map[string]interface{}{"uri": uri},
+		"position":     map[string]interface{}{"line": line, "character": character},
+	})
+
+	result, ok := got["result"].(map[string]interface{})
+	require.True(c.t, ok, "unexpected response: %v", got)
+
+	items, ok := result["items"].([]interface{})
+	require.True(c.t, ok)
+
+	return items
+}
+
+func (c *testClient) open(uri, languageID, text string) {
+	c.t.Helper()
+
+	c.notify("textDocument/didOpen", map[string]interface{}{
+		"textDocument": map[string]interface{}{
+			"uri":        uri,
+			"languageId": languageID,
+			"version":    1,
+			"text":       text,
+		},
+	})
+}
+
+func TestServer(t *testing.T) {
+	t.Parallel()
+
+	t.Run("requests before initialize are rejected", func(t *testing.T) {
+		t.Parallel()
+
+		client := newTestClient(t, expand.Options{})
+
+		got := client.call("textDocument/completion", map[string]interface{}{})
+		require.Contains(t, got, "error")
+
+		gotErr, ok := got["error"].(map[string]interface{})
+		require.True(t, ok)
+		assert.InDelta(t, codeServerNotInitialized, gotErr["code"], 0)
+	})
+
+	t.Run("lifecycle", func(t *testing.T) {
+		t.Parallel()
+
+		client := newTestClient(t, expand.Options{})
+
+		got := client.call("initialize", map[string]interface{}{})
+		assert.Equal(t, "xemmet", got["result"].(map[string]interface{})["serverInfo"].(map[string]interface{})["name"])
+
+		client.notify("initialized", map[string]interface{}{})
+
+		got = client.call("shutdown", nil)
+		assert.Contains(t, got, "result")
+		assert.Nil(t, got["result"])
+
+		client.notify("exit", nil)
+
+		require.NoError(t, <-client.done)
+	})
+
+	t.Run("exit without shutdown", func(t *testing.T) {
+		t.Parallel()
+
+		client := newTestClient(t, expand.Options{})
+
+		client.call("initialize", map[string]interface{}{})
+		client.notify("exit", nil)
+
+		require.ErrorIs(t, <-client.done, ErrExitWithoutShutdown)
+	})
+
+	t.Run("completion", func(t *testing.T) {
+		t.Parallel()
+
+		client := newTestClient(t, expand.Options{Indentation: "  ", Multiline: true})
+
+		client.call("initialize", map[string]interface{}{})
+		client.open("file:///index.html", "html", "<body>\n  ul>li*2{\\$5}\n</body>")
+		client.open("file:///feed.xml", "xml", "feed>entry")
+		client.open("file:///main.go", "go", "ul>li")
+		client.open("file:///void.html", "html", "div>br+img")
+
+		items := client.completion("file:///index.html", 1, 14)
+		require.Len(t, items, 1)
+
+		item, ok := items[0].(map[string]interface{})
+		require.True(t, ok)
+
+		assert.Equal(t, `ul>li*2{\$5}`, item["label"])
+		assert.Equal(t, map[string]interface{}{
+			"range": map[string]interface{}{
+				"start": map[string]interface{}{"line": float64(1), "character": float64(2)},
+				"end":   map[string]interface{}{"line": float64(1), "character": float64(14)},
+			},
+			"newText": "<ul>\n  <li>\\$5${1}</li>\n  <li>\\$5${2}</li>\n</ul>",
+		}, item["textEdit"])
+
+		items = client.completion("file:///feed.xml", 0, 10)
+		require.Len(t, items, 1)
+		assert.Equal(t, "<feed>\n  <entry />\n</feed>", items[0].(map[string]interface{})["textEdit"].(map[string]interface{})["newText"])
+
+		// void elements are not given content, only their attributes get tab stops
+		items = client.completion("file:///void.html", 0, 10)
+		require.Len(t, items, 1)
+		assert.Equal(t, "<div><br><img src=\"${1}\" alt=\"${2}\"></div>", items[0].(map[string]interface{})["textEdit"].(map[string]interface{})["newText"])
+
+		assert.Empty(t, client.completion("file:///main.go", 0, 5))
+		assert.Empty(t, client.completion("file:///index.html", 0, 0))
+		assert.Empty(t, client.completion("file:///index.html", 0, 6))
+		assert.Empty(t, client.completion("file:///index.html", 1, 1))
+		assert.Empty(t, client.completion("file:///unknown.html", 0, 0))
+
+		client.notify("textDocument/didChange", map[string]interface{}{
+			"textDocument":   map[string]interface{}{"uri": "file:///index.html", "version": 2},
+			"contentChanges": []interface{}{map[string]interface{}{"text": "árvíz p.x"}},
+		})
+
+		items = client.completion("file:///index.html", 0, 9)
+		require.Len(t, items, 1)
+		assert.Equal(t, "p.x", items[0].(map[string]interface{})["label"])
+	})
+
+	t.Run("unknown methods", func(t *testing.T) {
+		t.Parallel()
+
+		client := newTestClient(t, expand.Options{})
+
+		client.call("initialize", map[string]interface{}{})
+
+		got := client.call("textDocument/hover", map[string]interface{}{})
+		gotErr, ok := got["error"].(map[string]interface{})
+		require.True(t, ok)
+		assert.InDelta(t, codeMethodNotFound, gotErr["code"], 0)
+	})
+}
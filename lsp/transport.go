@@ -0,0 +1,93 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrMissingContentLength = errors.New("missing Content-Length header")
+	ErrInvalidHeader        = errors.New("invalid header")
+)
+
+const contentLengthHeader = "Content-Length"
+
+// transport reads and writes JSON-RPC messages framed with LSP base protocol headers.
+type transport struct {
+	reader *bufio.Reader
+	writer io.Writer
+	lock   *sync.Mutex
+}
+
+func newTransport(r io.Reader, w io.Writer) *transport {
+	return &transport{
+		reader: bufio.NewReader(r),
+		writer: w,
+		lock:   &sync.Mutex{},
+	}
+}
+
+func (t *transport) Read() ([]byte, error) {
+	contentLength := -1
+
+	for {
+		line, err := t.reader.ReadString('\n')
+		if err != nil {
+			return nil, err // nolint: wrapcheck
+		}
+
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break
+		}
+
+		name, value, found := strings.Cut(line, ":")
+		if !found {
+			return nil, errors.Wrapf(ErrInvalidHeader, "header: %q", line)
+		}
+
+		if !strings.EqualFold(strings.TrimSpace(name), contentLengthHeader) {
+			continue
+		}
+
+		contentLength, err = strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return nil, errors.Wrapf(ErrInvalidHeader, "header: %q", line)
+		}
+	}
+
+	if contentLength < 0 {
+		return nil, ErrMissingContentLength
+	}
+
+	body := make([]byte, contentLength)
+
+	if _, err := io.ReadFull(t.reader, body); err != nil {
+		return nil, errors.Wrap(err, "failed to read message body")
+	}
+
+	return body, nil
+}
+
+func (t *transport) Write(msg interface{}) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode message")
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if _, err := fmt.Fprintf(t.writer, "%s: %d\r\n\r\n%s", contentLengthHeader, len(body), body); err != nil {
+		return errors.Wrap(err, "failed to write message")
+	}
+
+	return nil
+}
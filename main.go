@@ -2,24 +2,14 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"log"
 	"os"
-	"strings"
 
-	"github.com/pkg/errors"
 	"github.com/urfave/cli/v2"
-)
-
-type Mode string
-
-const (
-	ModeHTML Mode = "html"
-	ModeXML  Mode = "xml"
-	ModeHTMX Mode = "htmx"
-)
 
-const (
-	defaultIndentation = "    "
+	"github.com/peteraba/xemmet/expand"
+	"github.com/peteraba/xemmet/lsp"
 )
 
 func main() {
@@ -29,12 +19,12 @@ func main() {
 		Flags: []cli.Flag{
 			&cli.StringFlag{
 				Name:  "mode",
-				Value: string(ModeHTML),
-				Usage: "Output mode (html, xml, htmx)",
+				Value: string(expand.ModeHTML),
+				Usage: "Output mode (html, xml, htmx, jsx)",
 			},
 			&cli.StringFlag{
 				Name:  "indentation",
-				Value: defaultIndentation,
+				Value: expand.DefaultIndentation,
 				Usage: "Indentation to apply (not multiline if empty)",
 			},
 			&cli.IntFlag{
@@ -45,25 +35,160 @@ func main() {
 			&cli.BoolFlag{
 				Name:  "inline",
 				Value: false,
-				Usage: "Enable debug mode",
+				Usage: "Render everything on a single line",
+			},
+			&cli.StringSliceFlag{
+				Name:  "inlineElements",
+				Usage: "Elements kept on the line of their parent if all its children are inline, defaults to the inline-level elements of the mode",
+			},
+			&cli.StringFlag{
+				Name:  "syntax",
+				Value: string(expand.SyntaxMarkup),
+				Usage: "Template syntax to write (markup, pug, haml, slim), markup is HTML, XML or JSX depending on the mode",
 			},
 			&cli.StringFlag{
 				Name:  "tabStop",
 				Value: "",
 				Usage: "Unique set of characters to surround variable names used for tabs stops (if empty, then tab stops will not be added)",
 			},
+			&cli.StringFlag{
+				Name:  "tabStopStyle",
+				Value: string(expand.TabStopWrapped),
+				Usage: "Tab stop style (wrapped, snippet)",
+			},
+			&cli.StringFlag{
+				Name:  "quote",
+				Value: string(expand.QuoteDouble),
+				Usage: "Quotes to use around attribute values (double, single)",
+			},
+			&cli.StringFlag{
+				Name:  "selfClosing",
+				Value: "",
+				Usage: "Self-closing style for empty elements (html, xhtml, xml), defaults to the mode's own style",
+			},
+			&cli.StringFlag{
+				Name:  "booleanAttributes",
+				Value: "",
+				Usage: "Style of boolean attributes (minimized, expanded), expanded for xml and xhtml by default",
+			},
+			&cli.BoolFlag{
+				Name:  "raw",
+				Value: false,
+				Usage: "Write attribute values and text without escaping, e.g. to inject template expressions",
+			},
+			&cli.Int64Flag{
+				Name:  "loremSeed",
+				Value: 0,
+				Usage: "Seed for lorem ipsum generation (random if 0)",
+			},
+			&cli.BoolFlag{
+				Name:  "stdin",
+				Value: false,
+				Usage: "Read abbreviations from stdin, one per line",
+			},
+			&cli.BoolFlag{
+				Name:    "null",
+				Aliases: []string{"0"},
+				Value:   false,
+				Usage:   "Abbreviations read from stdin are separated by NUL characters instead of new lines",
+			},
+			&cli.StringFlag{
+				Name:  "delimiter",
+				Value: `\n`,
+				Usage: "Delimiter written between expansions read from stdin (Go escape sequences are allowed)",
+			},
+			&cli.StringFlag{
+				Name:  "format",
+				Value: string(FormatText),
+				Usage: "Output format (text, json)",
+			},
+			&cli.BoolFlag{
+				Name:  "extract",
+				Value: false,
+				Usage: "Treat input as a full line of text and only expand the abbreviation before the cursor column",
+			},
+			&cli.IntFlag{
+				Name:  "column",
+				Value: -1,
+				Usage: "Cursor column for --extract, counted in runes from 0 (end of line if negative)",
+			},
+			&cli.BoolFlag{
+				Name:  "recover",
+				Value: false,
+				Usage: "Expand the valid part of broken abbreviations, reporting the issues as warnings",
+			},
+			&cli.StringSliceFlag{
+				Name:  "emmetSnippets",
+				Usage: "Snippet file in the format of Emmet (html.json or an editor's snippets.json), --snippets override them",
+			},
+			&cli.StringSliceFlag{
+				Name:  "snippets",
+				Usage: "Snippet file (json, yaml, toml) merged over the built-in snippets and the ones found in $XDG_CONFIG_HOME/xemmet",
+			},
+		},
+		Commands: []*cli.Command{
+			{
+				Name:  "lsp",
+				Usage: "Start a Language Server Protocol server on stdio offering expansions as completions",
+				Action: func(cCtx *cli.Context) error {
+					opts, err := optionsFromContext(cCtx)
+					if err != nil {
+						return err
+					}
+
+					return lsp.NewServer(os.Stdin, os.Stdout, opts).Run()
+				},
+			},
+			{
+				Name:      "wrap",
+				Usage:     "Wrap the content read from stdin with the abbreviation, e.g. ul>li* wraps each line in a list item",
+				ArgsUsage: "abbreviation",
+				Action: func(cCtx *cli.Context) error {
+					opts, err := optionsFromContext(cCtx)
+					if err != nil {
+						return err
+					}
+
+					content, err := io.ReadAll(os.Stdin)
+					if err != nil {
+						return err
+					}
+
+					abbr := cCtx.Args().First()
+
+					got, err := expand.Wrap(abbr, string(content), opts)
+					if err != nil {
+						return withCaret(err, abbr)
+					}
+
+					fmt.Print(got) // nolint: forbidigo
+
+					return nil
+				},
+			},
 		},
 		Action: func(cCtx *cli.Context) error {
-			var (
-				str         = cCtx.Args().First()
-				mode        = Mode(cCtx.String("mode"))
-				indentation = cCtx.String("indentation")
-				depth       = cCtx.Int("depth")
-				multiline   = !cCtx.Bool("inline")
-				tabStop     = cCtx.String("tabStop")
-			)
-
-			got, err := Xemmet(mode, str, indentation, depth, multiline, tabStop)
+			opts, err := optionsFromContext(cCtx)
+			if err != nil {
+				return err
+			}
+
+			expandFn, err := newExpander(expanderConfig{
+				format:   Format(cCtx.String("format")),
+				opts:     opts,
+				extract:  cCtx.Bool("extract"),
+				column:   cCtx.Int("column"),
+				warnings: os.Stderr,
+			})
+			if err != nil {
+				return err
+			}
+
+			if cCtx.Bool("stdin") || cCtx.Bool("null") {
+				return expandBatch(os.Stdin, os.Stdout, expandFn, cCtx.Bool("null"), unescapeDelimiter(cCtx.String("delimiter")))
+			}
+
+			got, err := expandFn(cCtx.Args().First())
 			if err != nil {
 				return err
 			}
@@ -79,33 +204,32 @@ func main() {
 	}
 }
 
-const (
-	ErrTokenizingMsg = "error tokenizing string"
-)
-
-func Xemmet(mode Mode, str string, indentation string, depth int, multiline bool, tabStopWrapper string) (string, error) {
-	l := NewLexer(mode)
-
-	// Create raw tokens
-	tokens, _, err := l.Tokenize([]rune(str), false)
+func optionsFromContext(cCtx *cli.Context) (expand.Options, error) {
+	snippets, err := loadSnippets(cCtx.StringSlice("emmetSnippets"), cCtx.StringSlice("snippets"), os.Stderr)
 	if err != nil {
-		return "", errors.Wrap(err, ErrTokenizingMsg)
+		return expand.Options{}, err
 	}
 
-	// Adjust tokens based on predefined rules
-	s := NewSnippeter(mode)
-	tokens = s.Walk(tokens...)
-
-	// Convert tokens to HTML/XML elements
-	elemList := Build(tokens, 1, 1)
-
-	// Render HTML/XML
-	builder := &strings.Builder{}
-	counter := NewCounter()
+	opts := expand.Options{
+		Mode:              expand.Mode(cCtx.String("mode")),
+		Indentation:       cCtx.String("indentation"),
+		Depth:             cCtx.Int("depth"),
+		Multiline:         !cCtx.Bool("inline"),
+		TabStop:           expand.TabStopStyle(cCtx.String("tabStopStyle")),
+		TabStopWrapper:    cCtx.String("tabStop"),
+		Quote:             expand.QuoteStyle(cCtx.String("quote")),
+		SelfClosing:       expand.SelfClosingStyle(cCtx.String("selfClosing")),
+		BooleanAttributes: expand.BooleanAttributeStyle(cCtx.String("booleanAttributes")),
+		Syntax:            expand.Syntax(cCtx.String("syntax")),
+		Raw:               cCtx.Bool("raw"),
+		LoremSeed:         cCtx.Int64("loremSeed"),
+		Recover:           cCtx.Bool("recover"),
+		Snippets:          snippets,
+	}
 
-	elemList.HTML(builder, counter, mode, indentation, depth, multiline, tabStopWrapper)
-	rawResult := builder.String()
+	if cCtx.IsSet("inlineElements") {
+		opts.InlineElements = cCtx.StringSlice("inlineElements")
+	}
 
-	// Finalize response
-	return strings.Trim(rawResult, "\n\t\r "), nil
+	return opts, opts.Validate()
 }
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/peteraba/xemmet/expand"
+)
+
+// loadSnippets loads the snippet file found in the config directory, then the Emmet snippet files and the snippet
+// files given. Snippets of later files override the ones of earlier files with the same mode and name. Emmet
+// snippets which can't be used are reported to warnings.
+func loadSnippets(emmetPaths, paths []string, warnings io.Writer) (expand.Snippets, error) {
+	var snippets expand.Snippets
+
+	if path := findSnippetFile(); path != "" {
+		loaded, err := expand.LoadSnippets(path)
+		if err != nil {
+			return nil, err
+		}
+
+		snippets = snippets.Merge(loaded)
+	}
+
+	for _, path := range emmetPaths {
+		loaded, skipped, err := expand.LoadEmmetSnippets(path)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, warning := range skipped {
+			fmt.Fprintf(warnings, "warning: %s\n", warning)
+		}
+
+		snippets = snippets.Merge(loaded)
+	}
+
+	for _, path := range paths {
+		loaded, err := expand.LoadSnippets(path)
+		if err != nil {
+			return nil, err
+		}
+
+		snippets = snippets.Merge(loaded)
+	}
+
+	return snippets, nil
+}
+
+// findSnippetFile returns the first snippet file found in the xemmet directory of configDir, or an empty string.
+func findSnippetFile() string {
+	dir := configDir()
+	if dir == "" {
+		return ""
+	}
+
+	for _, name := range expand.SnippetFileNames {
+		path := filepath.Join(dir, "xemmet", name)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+
+	return ""
+}
+
+// configDir returns $XDG_CONFIG_HOME, ~/.config if it's not set, on every OS. Unlike os.UserConfigDir it doesn't use
+// ~/Library/Application Support on macOS, to keep the config of a command line tool where users look for it.
+func configDir() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); filepath.IsAbs(dir) {
+		return dir
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(home, ".config")
+}
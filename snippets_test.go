@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/peteraba/xemmet/expand"
+)
+
+// nolint: paralleltest
+func TestLoadSnippets(t *testing.T) {
+	configDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configDir)
+
+	require.NoError(t, os.Mkdir(filepath.Join(configDir, "xemmet"), 0o700))
+	require.NoError(t, os.WriteFile(
+		filepath.Join(configDir, "xemmet", "snippets.yaml"),
+		[]byte("html:\n  card:\n    name: div\n  btn:x:\n    name: button\n"),
+		0o600,
+	))
+
+	override := filepath.Join(t.TempDir(), "team.json")
+	require.NoError(t, os.WriteFile(override, []byte(`{"html": {"card": {"name": "article"}}}`), 0o600))
+
+	t.Run("discovered", func(t *testing.T) {
+		got, err := loadSnippets(nil, nil, io.Discard)
+		require.NoError(t, err)
+
+		assert.Equal(t, expand.Snippets{
+			expand.ModeHTML: {"card": {Name: "div"}, "btn:x": {Name: "button"}},
+		}, got)
+	})
+
+	t.Run("given files override discovered ones", func(t *testing.T) {
+		got, err := loadSnippets(nil, []string{override}, io.Discard)
+		require.NoError(t, err)
+
+		assert.Equal(t, expand.Snippets{
+			expand.ModeHTML: {"card": {Name: "article"}, "btn:x": {Name: "button"}},
+		}, got)
+	})
+
+	t.Run("emmet snippets", func(t *testing.T) {
+		emmet := filepath.Join(t.TempDir(), "html.json")
+		require.NoError(t, os.WriteFile(emmet, []byte(`{"card": "section", "btn:x": "button[type=submit]", "c": "{<!-- ${0} -->}"}`), 0o600))
+
+		warnings := &bytes.Buffer{}
+
+		got, err := loadSnippets([]string{emmet}, []string{override}, warnings)
+		require.NoError(t, err)
+
+		assert.Equal(t, expand.Snippets{
+			expand.ModeHTML: {
+				"card":  {Name: "article"},
+				"btn:x": {Name: "button", Attributes: []expand.SnippetAttr{{Name: "type", Value: "submit"}}},
+			},
+		}, got)
+		assert.Contains(t, warnings.String(), `warning: snippet "c" skipped`)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := loadSnippets(nil, []string{filepath.Join(configDir, "missing.json")}, io.Discard)
+		assert.Error(t, err)
+	})
+}
+
+// nolint: paralleltest
+func TestConfigDir(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	t.Run("XDG_CONFIG_HOME", func(t *testing.T) {
+		t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "config"))
+
+		assert.Equal(t, filepath.Join(home, "config"), configDir())
+	})
+
+	t.Run("defaults to ~/.config", func(t *testing.T) {
+		t.Setenv("XDG_CONFIG_HOME", "")
+
+		assert.Equal(t, filepath.Join(home, ".config"), configDir())
+	})
+
+	t.Run("relative XDG_CONFIG_HOME is ignored", func(t *testing.T) {
+		t.Setenv("XDG_CONFIG_HOME", "config")
+
+		assert.Equal(t, filepath.Join(home, ".config"), configDir())
+	})
+}